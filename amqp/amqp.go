@@ -0,0 +1,72 @@
+// Package amqp provides a logger.Publisher publishing to RabbitMQ and
+// other AMQP 0-9-1 brokers.
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	logger "github.com/glepnir/zapecho"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Publisher publishes messages to the exchange given by their topic, with
+// publisher confirms, so a publish only succeeds once the broker has
+// taken responsibility for every message.
+type Publisher struct {
+	mu         sync.Mutex
+	ch         *amqp.Channel
+	routingKey string
+}
+
+var _ logger.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns a Publisher publishing on ch, which is put in
+// confirm mode. Messages are routed by their key, or by routingKey when
+// unkeyed.
+func NewPublisher(ch *amqp.Channel, routingKey string) (*Publisher, error) {
+	if err := ch.Confirm(false); err != nil {
+		return nil, err
+	}
+	return &Publisher{ch: ch, routingKey: routingKey}, nil
+}
+
+// Publish publishes msgs and waits for their confirmations.
+func (p *Publisher) Publish(ctx context.Context, msgs []logger.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(msgs))
+	for _, m := range msgs {
+		key := m.Key
+		if key == "" {
+			key = p.routingKey
+		}
+		dc, err := p.ch.PublishWithDeferredConfirmWithContext(ctx, m.Topic, key, false, false, amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			Timestamp:    m.Time,
+			Body:         m.Value,
+		})
+		if err != nil {
+			return err
+		}
+		confirms = append(confirms, dc)
+	}
+	for _, dc := range confirms {
+		ok, err := dc.WaitContext(ctx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("amqp: message %d nacked by the broker", dc.DeliveryTag)
+		}
+	}
+	return nil
+}
+
+// Close closes the channel.
+func (p *Publisher) Close() error {
+	return p.ch.Close()
+}
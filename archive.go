@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Uploader stores objects, such as in an S3 bucket. The s3 subpackage
+// provides an implementation.
+type Uploader interface {
+	Upload(ctx context.Context, key string, body io.Reader) error
+}
+
+// Archive configures an archive core.
+type Archive struct {
+	// Prefix is prepended to object keys, e.g. "access-logs/".
+	Prefix string
+
+	// KeyLayout is the time layout of the object key path, given the time
+	// of the first entry of the object in UTC.
+	// Optional. Default value "2006/01/02/15/".
+	KeyLayout string
+
+	// Encoder encodes the NDJSON lines.
+	// Optional. Default value a JSON encoder.
+	Encoder zapcore.Encoder
+
+	// Timeout bounds an upload.
+	// Optional. Default value 1m.
+	Timeout time.Duration
+
+	// Batching sets the object boundaries.
+	// Optional. Default value 100000 entries, 64 MiB or 5 minutes.
+	Batching Batching
+}
+
+// NewArchiveCore returns a core accumulating entries into gzipped NDJSON
+// objects uploaded through u once a Batching boundary is reached, for
+// long-term retention.
+func NewArchiveCore(u Uploader, a Archive, enab zapcore.LevelEnabler) *BatchCore {
+	if a.KeyLayout == "" {
+		a.KeyLayout = "2006/01/02/15/"
+	}
+	if a.Timeout <= 0 {
+		a.Timeout = time.Minute
+	}
+	if a.Batching.Size <= 0 {
+		a.Batching.Size = 100000
+	}
+	if a.Batching.Bytes <= 0 {
+		a.Batching.Bytes = 64 << 20
+	}
+	if a.Batching.Interval <= 0 {
+		a.Batching.Interval = 5 * time.Minute
+	}
+
+	send := func(batch []batchEntry) error {
+		var body bytes.Buffer
+		zw := gzip.NewWriter(&body)
+		for _, e := range batch {
+			zw.Write(e.data)
+			zw.Write([]byte{'\n'})
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+
+		var id [4]byte
+		rand.Read(id[:])
+		first := batch[0].time.UTC()
+		key := a.Prefix + first.Format(a.KeyLayout) +
+			first.Format("20060102T150405.000000000Z") + "-" + hex.EncodeToString(id[:]) + ".ndjson.gz"
+
+		ctx, cancel := context.WithTimeout(context.Background(), a.Timeout)
+		defer cancel()
+		return u.Upload(ctx, key, bytes.NewReader(body.Bytes()))
+	}
+
+	return newBatchCore(enab, a.Batching, encodeJSON(a.Encoder), send)
+}
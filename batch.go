@@ -0,0 +1,341 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Batching configures how a batching core groups entries before sending
+// them.
+type Batching struct {
+	// Size is the number of entries sent at once.
+	// Optional. Default value 1000.
+	Size int
+
+	// Bytes is the encoded size a batch is sent at.
+	// Optional. Default value 1 MiB.
+	Bytes int
+
+	// Interval is the longest time an entry waits to be sent.
+	// Optional. Default value 1s.
+	Interval time.Duration
+
+	// Limit is the number of entries buffered while sending fails. The
+	// oldest entries are dropped beyond it.
+	// Optional. Default value 10 times Size.
+	Limit int
+
+	// Retry is the retry policy of failed sends. Batches still failing are
+	// buffered again and retried with the next batch.
+	// Optional. Default value the Retry defaults.
+	Retry Retry
+
+	// OnError is called with the errors of background sends.
+	// Optional. Default value nil, errors are written to stderr.
+	OnError func(error)
+}
+
+func (b Batching) withDefaults() Batching {
+	if b.Size <= 0 {
+		b.Size = 1000
+	}
+	if b.Bytes <= 0 {
+		b.Bytes = 1 << 20
+	}
+	if b.Interval <= 0 {
+		b.Interval = time.Second
+	}
+	if b.Limit <= 0 {
+		b.Limit = 10 * b.Size
+	}
+	if b.OnError == nil {
+		b.OnError = func(err error) {
+			fmt.Fprintf(os.Stderr, "%v logger: batch send error: %v\n", time.Now(), err)
+		}
+	}
+	return b
+}
+
+// batchEntry is an encoded entry waiting to be sent.
+type batchEntry struct {
+	// key groups entries within a batch, e.g. by Loki stream.
+	key  string
+	time time.Time
+	data []byte
+	// value is the structured entry of sinks converting it when sending,
+	// or the labels of a Loki stream, and valueSize its estimated encoded
+	// size.
+	value     interface{}
+	valueSize int
+}
+
+func (e batchEntry) size() int {
+	return len(e.data) + e.valueSize
+}
+
+// batchEncoder encodes an entry for a batching core.
+type batchEncoder func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error)
+
+// batchSender sends a batch of entries.
+type batchSender func(batch []batchEntry) error
+
+// permanentError is a send error retrying cannot fix, such as a rejected
+// request. The batch is dropped.
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// partialError is a send error of which only the entries at the failed
+// indexes of the batch are retried, the others having been sent.
+type partialError struct {
+	err    error
+	failed []int
+}
+
+func (e partialError) Error() string { return e.err.Error() }
+func (e partialError) Unwrap() error { return e.err }
+
+// BatchCore is a zapcore.Core buffering encoded entries and sending them
+// in batches from a background goroutine, the base of the HTTP and cloud
+// sinks. Sync sends the buffered entries, Close also stops the goroutine.
+type BatchCore struct {
+	zapcore.LevelEnabler
+	fields []zapcore.Field
+	encode batchEncoder
+	b      *batcher
+}
+
+func newBatchCore(enab zapcore.LevelEnabler, cfg Batching, encode batchEncoder, send batchSender) *BatchCore {
+	b := &batcher{
+		config: cfg.withDefaults(),
+		send:   send,
+		flushc: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+	b.wg.Add(1)
+	go b.run()
+	c := &BatchCore{LevelEnabler: enab, encode: encode, b: b}
+	closers.add(c)
+	return c
+}
+
+func (c *BatchCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *BatchCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *BatchCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.fields) > 0 {
+		fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	}
+	e, err := c.encode(ent, fields)
+	if err != nil {
+		return err
+	}
+	c.b.add(e)
+	return nil
+}
+
+// Sync sends the buffered entries.
+func (c *BatchCore) Sync() error {
+	return c.b.flush()
+}
+
+// Close sends the buffered entries and stops the background goroutine.
+// Entries written after Close are dropped.
+func (c *BatchCore) Close() error {
+	closers.remove(c)
+	return c.b.close()
+}
+
+// Healthy reports whether the last send succeeded.
+func (c *BatchCore) Healthy() bool {
+	return !c.b.failing.Load()
+}
+
+// Dropped returns the number of entries dropped because the buffer was
+// full.
+func (c *BatchCore) Dropped() uint64 {
+	return c.b.dropped.Load()
+}
+
+// batcher buffers entries, shared by the cores derived with With.
+type batcher struct {
+	config Batching
+	send   batchSender
+
+	mu      sync.Mutex
+	entries []batchEntry
+	size    int
+	closed  bool
+	dropped atomic.Uint64
+	failing atomic.Bool
+
+	// sendMu serializes sends, so entries are sent in order.
+	sendMu sync.Mutex
+	flushc chan struct{}
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+func (b *batcher) add(e batchEntry) {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		b.dropped.Add(1)
+		return
+	}
+	b.entries = append(b.entries, e)
+	b.size += e.size()
+	b.trimLocked()
+	full := len(b.entries) >= b.config.Size || b.size >= b.config.Bytes
+	b.mu.Unlock()
+
+	if full {
+		select {
+		case b.flushc <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// trimLocked drops the oldest entries beyond the buffer limit.
+func (b *batcher) trimLocked() {
+	if n := len(b.entries) - b.config.Limit; n > 0 {
+		for _, e := range b.entries[:n] {
+			b.size -= e.size()
+		}
+		b.entries = append(b.entries[:0:0], b.entries[n:]...)
+		b.dropped.Add(uint64(n))
+	}
+}
+
+func (b *batcher) run() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-b.flushc:
+		case <-b.done:
+			return
+		}
+		if err := b.flush(); err != nil {
+			b.config.OnError(err)
+		}
+	}
+}
+
+// flush sends the buffered entries in batches. Entries of a failed batch
+// are buffered again, to be retried with the next flush, unless the
+// failure is permanent.
+func (b *batcher) flush() error {
+	b.sendMu.Lock()
+	defer b.sendMu.Unlock()
+	for {
+		batch := b.next()
+		if len(batch) == 0 {
+			return nil
+		}
+		err := b.config.Retry.do(func() error {
+			err := b.send(batch)
+			var perr partialError
+			if errors.As(err, &perr) {
+				// Only the failed entries are sent again.
+				failed := make([]batchEntry, 0, len(perr.failed))
+				for _, i := range perr.failed {
+					failed = append(failed, batch[i])
+				}
+				batch = failed
+			}
+			return err
+		})
+		b.failing.Store(err != nil)
+		if err != nil {
+			var perr permanentError
+			if errors.As(err, &perr) {
+				b.dropped.Add(uint64(len(batch)))
+				return err
+			}
+			b.mu.Lock()
+			b.entries = append(batch, b.entries...)
+			for _, e := range batch {
+				b.size += e.size()
+			}
+			b.trimLocked()
+			b.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// next removes the next batch from the buffer.
+func (b *batcher) next() []batchEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n, size := 0, 0
+	for n < len(b.entries) && n < b.config.Size {
+		if n > 0 && size+b.entries[n].size() > b.config.Bytes {
+			break
+		}
+		size += b.entries[n].size()
+		n++
+	}
+	batch := b.entries[:n:n]
+	b.entries = b.entries[n:]
+	b.size -= size
+	return batch
+}
+
+func (b *batcher) close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.mu.Unlock()
+
+	close(b.done)
+	b.wg.Wait()
+	return b.flush()
+}
+
+// encodeJSON returns an encoder writing entries with enc, a JSON encoder
+// with the production encoder config when nil.
+func encodeJSON(enc zapcore.Encoder) batchEncoder {
+	if enc == nil {
+		enc = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	}
+	return func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		buf, err := enc.EncodeEntry(ent, fields)
+		if err != nil {
+			return batchEntry{}, err
+		}
+		defer buf.Free()
+		data := buf.Bytes()
+		if n := len(data); n > 0 && data[n-1] == '\n' {
+			data = data[:n-1]
+		}
+		return batchEntry{time: ent.Time, data: append([]byte(nil), data...)}, nil
+	}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultBodyLimit is the default number of body bytes captured.
+const DefaultBodyLimit = 4 << 10
+
+// DefaultBodyContentTypes are the media types captured when none are
+// configured.
+var DefaultBodyContentTypes = []string{
+	echo.MIMEApplicationJSON,
+}
+
+// captureRequestBody reads up to limit bytes of the request body and
+// replays them to the handler ahead of the unread rest of the body.
+func captureRequestBody(c echo.Context, limit int64, types []string) ([]byte, bool) {
+	req := c.Request()
+	if req.Body == nil || req.Body == http.NoBody || !matchContentType(req.Header.Get(echo.HeaderContentType), types) {
+		return nil, false
+	}
+
+	b, err := io.ReadAll(io.LimitReader(req.Body, limit))
+	req.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(b), req.Body),
+		Closer: req.Body,
+	}
+	if err != nil {
+		return nil, false
+	}
+	return b, true
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// countingReader counts the bytes read from the request body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// countRequestBody replaces the request body with a counting reader.
+func countRequestBody(c echo.Context) *countingReader {
+	req := c.Request()
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	r := &countingReader{ReadCloser: req.Body}
+	req.Body = r
+	return r
+}
+
+// matchContentType reports whether the media type of contentType is one of
+// types. A type may end in "/*" to match any subtype.
+func matchContentType(contentType string, types []string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range types {
+		if t == mt || strings.HasSuffix(t, "/*") && strings.HasPrefix(mt, t[:len(t)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyRecorder records up to limit bytes written to the response, once
+// its status is known to be at least minStatus.
+type bodyRecorder struct {
+	http.ResponseWriter
+	buf       bytes.Buffer
+	limit     int
+	minStatus int
+	status    int
+}
+
+func (r *bodyRecorder) WriteHeader(code int) {
+	if r.status == 0 && code >= http.StatusOK {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *bodyRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if r.status >= r.minStatus {
+		if n := r.limit - r.buf.Len(); n > 0 {
+			if n > len(b) {
+				n = len(b)
+			}
+			r.buf.Write(b[:n])
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *bodyRecorder) Flush() {
+	if err := http.NewResponseController(r.ResponseWriter).Flush(); err != nil && errors.Is(err, http.ErrNotSupported) {
+		panic(errors.New("response writer flushing is not supported"))
+	}
+}
+
+func (r *bodyRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(r.ResponseWriter).Hijack()
+}
+
+func (r *bodyRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,59 @@
+package logger
+
+import "strings"
+
+// DefaultBotPatterns are the user agent substrings detected as bots when
+// Config.BotPatterns is empty: search engine crawlers, command line
+// clients and monitoring agents.
+var DefaultBotPatterns = []string{
+	"bot",
+	"crawler",
+	"spider",
+	"slurp",
+	"curl/",
+	"wget/",
+	"python-requests/",
+	"go-http-client/",
+	"okhttp/",
+	"headlesschrome",
+	"kube-probe/",
+	"elb-healthchecker/",
+	"googlehc/",
+	"pingdom",
+	"uptimerobot",
+	"statuscake",
+	"datadog",
+	"newrelicpinger",
+	"site24x7",
+}
+
+var defaultBots = newBotMatcher(DefaultBotPatterns)
+
+// botMatcher detects the user agents of bots.
+type botMatcher struct {
+	patterns []string
+}
+
+func newBotMatcher(patterns []string) *botMatcher {
+	if len(patterns) == 0 {
+		patterns = DefaultBotPatterns
+	}
+	m := &botMatcher{patterns: make([]string, len(patterns))}
+	for i, p := range patterns {
+		m.patterns[i] = strings.ToLower(p)
+	}
+	return m
+}
+
+func (m *botMatcher) match(ua string) bool {
+	if m == nil || ua == "" {
+		return false
+	}
+	ua = strings.ToLower(ua)
+	for _, p := range m.patterns {
+		if strings.Contains(ua, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// BreakerPolicy is what a circuit breaker does with entries while the
+// sink is unhealthy.
+type BreakerPolicy int
+
+// Circuit breaker policies.
+const (
+	// BreakerDrop drops entries.
+	BreakerDrop BreakerPolicy = iota
+	// BreakerBuffer writes entries to a local file, replayed once the sink
+	// recovers.
+	BreakerBuffer
+	// BreakerBlock blocks writes until the sink recovers.
+	BreakerBlock
+)
+
+// errBreakerOpen is returned for entries dropped by an open breaker.
+var errBreakerOpen = errors.New("logger: circuit breaker open, entry dropped")
+
+// Breaker configures a circuit breaker core.
+type Breaker struct {
+	// Threshold is the number of consecutive failed writes opening the
+	// breaker.
+	// Optional. Default value 5.
+	Threshold int
+
+	// Cooldown is the time the breaker stays open before a write is let
+	// through to probe the sink.
+	// Optional. Default value 30s.
+	Cooldown time.Duration
+
+	// Policy is applied to the entries written while the breaker is open.
+	// Optional. Default value BreakerDrop.
+	Policy BreakerPolicy
+
+	// BufferPath is the file of the BreakerBuffer policy.
+	BufferPath string
+}
+
+// BreakerStats are the counters of a circuit breaker core.
+type BreakerStats struct {
+	Written  uint64
+	Failed   uint64
+	Dropped  uint64
+	Buffered uint64
+	Open     bool
+}
+
+// BreakerCore wraps a sink core with a circuit breaker, so an unhealthy
+// downstream costs neither a timeout per entry nor unbounded memory.
+type BreakerCore struct {
+	core   zapcore.Core
+	buffer zapcore.Core
+	state  *breakerState
+}
+
+type breakerState struct {
+	config Breaker
+	root   zapcore.Core
+	disk   *diskBuffer
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	failures int
+	openedAt time.Time
+	open     bool
+
+	written, failed, dropped, buffered atomic.Uint64
+}
+
+// NewBreakerCore returns core wrapped with the circuit breaker b.
+func NewBreakerCore(core zapcore.Core, b Breaker) (*BreakerCore, error) {
+	if b.Threshold <= 0 {
+		b.Threshold = 5
+	}
+	if b.Cooldown <= 0 {
+		b.Cooldown = 30 * time.Second
+	}
+	s := &breakerState{config: b, root: core}
+	s.cond = sync.NewCond(&s.mu)
+	c := &BreakerCore{core: core, state: s}
+	if b.Policy == BreakerBuffer {
+		disk, err := openDiskBuffer(b.BufferPath, core)
+		if err != nil {
+			return nil, err
+		}
+		s.disk, c.buffer = disk, disk.core
+	}
+	return c, nil
+}
+
+func (c *BreakerCore) Enabled(l zapcore.Level) bool {
+	return c.core.Enabled(l)
+}
+
+func (c *BreakerCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &BreakerCore{core: c.core.With(fields), state: c.state}
+	if c.buffer != nil {
+		clone.buffer = c.buffer.With(fields)
+	}
+	return clone
+}
+
+func (c *BreakerCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *BreakerCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	s := c.state
+	if !s.allow() {
+		switch s.config.Policy {
+		case BreakerBuffer:
+			s.buffered.Add(1)
+			return c.buffer.Write(ent, fields)
+		case BreakerBlock:
+			s.wait()
+		default:
+			s.dropped.Add(1)
+			return errBreakerOpen
+		}
+	}
+
+	err := c.core.Write(ent, fields)
+	if hr, ok := c.core.(healthReporter); ok && err == nil && !hr.Healthy() {
+		// The entry is buffered by the core, it is not lost.
+		s.record(errBreakerOpen)
+		return nil
+	}
+	s.record(err)
+	return err
+}
+
+func (c *BreakerCore) Sync() error {
+	if c.buffer != nil {
+		c.buffer.Sync()
+	}
+	return c.core.Sync()
+}
+
+// Stats returns the counters of the breaker.
+func (c *BreakerCore) Stats() BreakerStats {
+	s := c.state
+	s.mu.Lock()
+	open := s.open
+	s.mu.Unlock()
+	return BreakerStats{
+		Written:  s.written.Load(),
+		Failed:   s.failed.Load(),
+		Dropped:  s.dropped.Load(),
+		Buffered: s.buffered.Load(),
+		Open:     open,
+	}
+}
+
+// Close closes the buffer file of the BreakerBuffer policy.
+func (c *BreakerCore) Close() error {
+	if c.state.disk != nil {
+		return c.state.disk.close()
+	}
+	return nil
+}
+
+// allow reports whether a write may go to the sink: the breaker is closed,
+// or open past its cooldown, in which case the write probes the sink.
+func (s *breakerState) allow() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.open {
+		return true
+	}
+	if time.Since(s.openedAt) >= s.config.Cooldown {
+		// Half-open: let this write through, and hold the others back
+		// for another cooldown.
+		s.openedAt = time.Now()
+		return true
+	}
+	return false
+}
+
+// wait blocks until the breaker closes or its cooldown elapses.
+func (s *breakerState) wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.open && time.Since(s.openedAt) < s.config.Cooldown {
+		timer := time.AfterFunc(s.config.Cooldown-time.Since(s.openedAt), s.cond.Broadcast)
+		s.cond.Wait()
+		timer.Stop()
+	}
+}
+
+func (s *breakerState) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.failed.Add(1)
+		s.failures++
+		if s.failures >= s.config.Threshold && !s.open {
+			s.open, s.openedAt = true, time.Now()
+		}
+		return
+	}
+	s.written.Add(1)
+	wasOpen := s.open
+	s.failures, s.open = 0, false
+	if wasOpen {
+		s.cond.Broadcast()
+		if s.disk != nil {
+			s.disk.replay(s.root)
+		}
+	}
+}
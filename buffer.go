@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// BufferedWriter buffers writes to a zapcore.WriteSyncer, flushing when the
+// buffer is full, every flush interval and on Sync, so heavy traffic does
+// not serialize on write syscalls. Entries still buffered when the process
+// exits are lost unless Flush or Stop is called first.
+type BufferedWriter struct {
+	*zapcore.BufferedWriteSyncer
+}
+
+// NewBufferedWriter returns a BufferedWriter writing to ws. A size or
+// interval of 0 selects the zapcore.BufferedWriteSyncer defaults of
+// 256 kB and 30 seconds. The writer is flushed by the package Flush.
+func NewBufferedWriter(ws zapcore.WriteSyncer, size int, interval time.Duration) *BufferedWriter {
+	w := &BufferedWriter{&zapcore.BufferedWriteSyncer{
+		WS:            ws,
+		Size:          size,
+		FlushInterval: interval,
+	}}
+	buffers.add(w)
+	return w
+}
+
+// Flush writes the buffered entries.
+func (w *BufferedWriter) Flush() error {
+	return w.Sync()
+}
+
+// Stop flushes the buffer and stops the periodic flushing.
+func (w *BufferedWriter) Stop() error {
+	buffers.remove(w)
+	return w.BufferedWriteSyncer.Stop()
+}
+
+// buffers are the BufferedWriters not stopped yet.
+var buffers = &bufferRegistry{writers: make(map[*BufferedWriter]struct{})}
+
+type bufferRegistry struct {
+	mu      sync.Mutex
+	writers map[*BufferedWriter]struct{}
+}
+
+func (r *bufferRegistry) add(w *BufferedWriter) {
+	r.mu.Lock()
+	r.writers[w] = struct{}{}
+	r.mu.Unlock()
+}
+
+func (r *bufferRegistry) remove(w *BufferedWriter) {
+	r.mu.Lock()
+	delete(r.writers, w)
+	r.mu.Unlock()
+}
+
+func (r *bufferRegistry) all() []*BufferedWriter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make([]*BufferedWriter, 0, len(r.writers))
+	for w := range r.writers {
+		all = append(all, w)
+	}
+	return all
+}
+
+// Flush flushes every BufferedWriter, including those of loggers built
+// with WithBuffering.
+func Flush() error {
+	var errs []error
+	for _, w := range buffers.all() {
+		errs = append(errs, w.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// WithBuffering buffers a logger's outputs in BufferedWriters of the given
+// size and flush interval.
+func WithBuffering(size int, interval time.Duration) Option {
+	return func(o *options) {
+		o.buffering = &buffering{size: size, interval: interval}
+	}
+}
+
+type buffering struct {
+	size     int
+	interval time.Duration
+}
+
+func (b *buffering) wrap(ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+	if b == nil {
+		return ws
+	}
+	return NewBufferedWriter(ws, b.size, b.interval)
+}
@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+const clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+var encoderPool = buffer.NewPool()
+
+// clfEncoder renders access log entries in the Apache common or combined
+// log format. Entries that are not access log entries are dropped.
+type clfEncoder struct {
+	*zapcore.MapObjectEncoder
+	names    FieldNames
+	combined bool
+}
+
+// NewCLFEncoder returns an encoder rendering access log entries, whose
+// fields are named by names, in the Apache Common Log Format, or the
+// Combined Log Format when combined is true. Other entries are dropped, and
+// HTTP fields grouped by Config.HTTPRequestKey are not found.
+func NewCLFEncoder(names FieldNames, combined bool) zapcore.Encoder {
+	return &clfEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		names:            names.withDefaults(),
+		combined:         combined,
+	}
+}
+
+// NewCLFCore returns a core writing access log entries to ws in the Apache
+// Common or Combined Log Format.
+func NewCLFCore(ws zapcore.WriteSyncer, enab zapcore.LevelEnabler, names FieldNames, combined bool) zapcore.Core {
+	return zapcore.NewCore(NewCLFEncoder(names, combined), ws, enab)
+}
+
+// WithCLFOutput tees a logger's entries to a Combined Log Format core
+// writing to ws, next to the logger's own core.
+func WithCLFOutput(ws zapcore.WriteSyncer, names FieldNames) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, NewCLFCore(ws, core, names, true))
+	})
+}
+
+func (e *clfEncoder) Clone() zapcore.Encoder {
+	return &clfEncoder{
+		MapObjectEncoder: e.cloneFields(),
+		names:            e.names,
+		combined:         e.combined,
+	}
+}
+
+func (e *clfEncoder) cloneFields() *zapcore.MapObjectEncoder {
+	m := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		m.Fields[k] = v
+	}
+	return m
+}
+
+func (e *clfEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	m := e.cloneFields()
+	for _, f := range fields {
+		f.AddTo(m)
+	}
+
+	buf := encoderPool.Get()
+	request := clfRequest(m.Fields, e.names)
+	if request == "" {
+		return buf, nil
+	}
+
+	buf.AppendString(clfValue(m.Fields[e.names.RemoteIP]))
+	buf.AppendString(" - ")
+	buf.AppendString(clfValue(m.Fields[e.names.AuthUser]))
+	buf.AppendString(" [")
+	buf.AppendTime(ent.Time, clfTimeLayout)
+	buf.AppendString("] ")
+	buf.AppendString(strconv.Quote(request))
+	buf.AppendByte(' ')
+	buf.AppendString(clfValue(m.Fields[e.names.Status]))
+	buf.AppendByte(' ')
+	if size, ok := m.Fields[e.names.Size].(int64); ok && size > 0 {
+		buf.AppendInt(size)
+	} else {
+		buf.AppendByte('-')
+	}
+	if e.combined {
+		buf.AppendByte(' ')
+		buf.AppendString(strconv.Quote(clfValue(m.Fields[e.names.Referer])))
+		buf.AppendByte(' ')
+		buf.AppendString(strconv.Quote(clfValue(m.Fields[e.names.UserAgent])))
+	}
+	buf.AppendString(zapcore.DefaultLineEnding)
+
+	return buf, nil
+}
+
+// clfRequest returns the request line of an access log entry, or "" when
+// the entry has none.
+func clfRequest(fields map[string]interface{}, names FieldNames) string {
+	method, _ := fields[names.Method].(string)
+	uri, _ := fields[names.URI].(string)
+	var request string
+	if method != "" && uri != "" {
+		request = method + " " + uri
+	} else if r, ok := fields[names.Request].(string); ok {
+		request = r
+	}
+	if proto, ok := fields[names.Protocol].(string); ok && request != "" && proto != "" {
+		request += " " + proto
+	}
+	return request
+}
+
+// clfValue formats a field value, "-" when it is missing or empty.
+func clfValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if v == "" {
+			return "-"
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
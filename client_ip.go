@@ -0,0 +1,199 @@
+package logger
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Client IP headers.
+const (
+	HeaderForwarded      = "Forwarded"
+	HeaderCFConnectingIP = "CF-Connecting-IP"
+)
+
+// clientIPKey is the echo.Context key the middleware stores the resolved
+// client IP under for enrichers.
+const clientIPKey = "zapecho.client_ip"
+
+// ClientIP returns the client IP resolved by the middleware, honoring
+// Config.TrustedProxies, for use in enrichers. It returns false when
+// Config.Privacy is set, the IP then being known only anonymized, or
+// outside the middleware.
+func ClientIP(c echo.Context) (string, bool) {
+	ip, ok := c.Get(clientIPKey).(string)
+	return ip, ok
+}
+
+// DefaultIPHeaders is the default precedence of the headers the client IP
+// is read from when the peer is a trusted proxy.
+var DefaultIPHeaders = []string{
+	HeaderForwarded,
+	echo.HeaderXForwardedFor,
+	echo.HeaderXRealIP,
+	HeaderCFConnectingIP,
+}
+
+// ipResolver resolves the client IP, only trusting the headers set by
+// trusted proxies.
+type ipResolver struct {
+	trusted []netip.Prefix
+	headers []string
+}
+
+func newIPResolver(trusted []netip.Prefix, headers []string) *ipResolver {
+	if len(trusted) == 0 {
+		return nil
+	}
+	if len(headers) == 0 {
+		headers = DefaultIPHeaders
+	}
+	return &ipResolver{trusted: trusted, headers: canonicalHeaderKeys(headers)}
+}
+
+// clientIP returns the resolved client IP, echo.Context.RealIP when the
+// resolver is nil.
+func (r *ipResolver) clientIP(c echo.Context) string {
+	if r == nil {
+		return c.RealIP()
+	}
+	req := c.Request()
+	peer, ok := parseIP(req.RemoteAddr)
+	if !ok {
+		return req.RemoteAddr
+	}
+	if !r.isTrusted(peer) {
+		return peer.String()
+	}
+
+	for _, h := range r.headers {
+		var chain []string
+		switch h {
+		case HeaderForwarded:
+			chain = forwardedFor(req.Header)
+		case echo.HeaderXForwardedFor:
+			for _, v := range req.Header.Values(h) {
+				chain = append(chain, strings.Split(v, ",")...)
+			}
+		default:
+			chain = req.Header.Values(h)
+		}
+		if ip, ok := r.fromChain(chain); ok {
+			return ip.String()
+		}
+	}
+	return peer.String()
+}
+
+// fromChain returns the rightmost untrusted address of a proxy chain, or
+// its leftmost address when every proxy is trusted.
+func (r *ipResolver) fromChain(chain []string) (netip.Addr, bool) {
+	var first netip.Addr
+	for i := len(chain) - 1; i >= 0; i-- {
+		ip, ok := parseIP(chain[i])
+		if !ok {
+			// An unparsable hop cannot be trusted past.
+			return first, first.IsValid()
+		}
+		if !r.isTrusted(ip) {
+			return ip, true
+		}
+		first = ip
+	}
+	return first, first.IsValid()
+}
+
+func (r *ipResolver) isTrusted(ip netip.Addr) bool {
+	for _, p := range r.trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the for= addresses of the RFC 7239 Forwarded
+// headers.
+func forwardedFor(h http.Header) []string {
+	var chain []string
+	for _, v := range h.Values(HeaderForwarded) {
+		for _, elem := range strings.Split(v, ",") {
+			for _, pair := range strings.Split(elem, ";") {
+				k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(k, "for") {
+					chain = append(chain, strings.Trim(v, `"`))
+				}
+			}
+		}
+	}
+	return chain
+}
+
+// parseIP parses an IP address, optionally bracketed and with a port.
+func parseIP(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+	ip, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
+// normalizeIP returns ip, stripped of brackets and port, in canonical form,
+// or the network of its first bits when bits is positive for IPv6
+// addresses. Unparsable values are returned unchanged.
+func normalizeIP(ip string, bits int) string {
+	addr, ok := parseIP(ip)
+	if !ok {
+		return ip
+	}
+	if bits > 0 && addr.Is6() {
+		if p, err := addr.Prefix(bits); err == nil {
+			return p.String()
+		}
+	}
+	return addr.String()
+}
+
+// peerFields returns the peer address, with its port apart when
+// FieldNames.PeerPort is set.
+func peerFields(addr string, names FieldNames) []zapcore.Field {
+	if names.PeerPort != "" {
+		if host, port, err := net.SplitHostPort(addr); err == nil {
+			if p, err := strconv.Atoi(port); err == nil {
+				return []zapcore.Field{zap.String(names.PeerAddress, host), zap.Int(names.PeerPort, p)}
+			}
+		}
+	}
+	return []zapcore.Field{zap.String(names.PeerAddress, addr)}
+}
+
+// hostFields returns the request host, with its port apart when
+// FieldNames.HostPort is set. The port defaults to the one of the scheme.
+func hostFields(c echo.Context, names FieldNames) []zapcore.Field {
+	host := c.Request().Host
+	if names.HostPort == "" {
+		return []zapcore.Field{zap.String(names.Host, host)}
+	}
+	port := 80
+	if c.Scheme() == "https" {
+		port = 443
+	}
+	if h, p, err := net.SplitHostPort(host); err == nil {
+		host = h
+		if n, err := strconv.Atoi(p); err == nil {
+			port = n
+		}
+	}
+	return []zapcore.Field{zap.String(names.Host, host), zap.Int(names.HostPort, port)}
+}
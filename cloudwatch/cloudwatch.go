@@ -0,0 +1,198 @@
+// Package cloudwatch provides a logger.Publisher sending to Amazon
+// CloudWatch Logs.
+package cloudwatch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	logger "github.com/glepnir/zapecho"
+)
+
+// PutLogEvents limits.
+const (
+	maxBatchEvents = 10000
+	maxBatchBytes  = 1048576
+	eventOverhead  = 26
+	maxBatchSpan   = 24 * time.Hour
+	maxRetries     = 5
+)
+
+// Publisher puts messages as log events into the log stream given by
+// their topic, within a log group. Use it with logger.NewPublisherCore:
+//
+//	core := logger.NewPublisherCore(
+//		cloudwatch.NewPublisher(client, "/ecs/api", true),
+//		logger.Publishing{Topic: taskID},
+//		zapcore.InfoLevel,
+//	)
+type Publisher struct {
+	client *cloudwatchlogs.Client
+	group  string
+	create bool
+
+	mu      sync.Mutex
+	tokens  map[string]*string
+	created map[string]bool
+}
+
+var _ logger.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns a Publisher putting events into group. With create,
+// missing log groups and streams are created.
+func NewPublisher(client *cloudwatchlogs.Client, group string, create bool) *Publisher {
+	return &Publisher{
+		client:  client,
+		group:   group,
+		create:  create,
+		tokens:  make(map[string]*string),
+		created: make(map[string]bool),
+	}
+}
+
+// Publish puts msgs, split to respect the PutLogEvents limits. Throttled
+// requests are retried with backoff. When some requests fail, the others
+// are still sent and a *logger.PublishError lists the unsent messages.
+func (p *Publisher) Publish(ctx context.Context, msgs []logger.Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	byStream := make(map[string][]event)
+	var streams []string
+	for i, m := range msgs {
+		if _, ok := byStream[m.Topic]; !ok {
+			streams = append(streams, m.Topic)
+		}
+		byStream[m.Topic] = append(byStream[m.Topic], event{index: i, event: types.InputLogEvent{
+			Message:   aws.String(string(m.Value)),
+			Timestamp: aws.Int64(m.Time.UnixMilli()),
+		}})
+	}
+
+	var errs []error
+	var failed []int
+	for _, stream := range streams {
+		events := byStream[stream]
+		if err := p.ensureStream(ctx, stream); err != nil {
+			errs = append(errs, err)
+			failed = appendIndexes(failed, events)
+			continue
+		}
+		// Events must be in chronological order.
+		sort.SliceStable(events, func(i, j int) bool {
+			return *events[i].event.Timestamp < *events[j].event.Timestamp
+		})
+		for len(events) > 0 {
+			n := batchLen(events)
+			if err := p.put(ctx, stream, events[:n]); err != nil {
+				// Later events of the stream would be out of order.
+				errs = append(errs, err)
+				failed = appendIndexes(failed, events)
+				break
+			}
+			events = events[n:]
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.Ints(failed)
+	return &logger.PublishError{Err: errors.Join(errs...), Failed: failed}
+}
+
+// event is a log event and the index of its message.
+type event struct {
+	index int
+	event types.InputLogEvent
+}
+
+func appendIndexes(indexes []int, events []event) []int {
+	for _, e := range events {
+		indexes = append(indexes, e.index)
+	}
+	return indexes
+}
+
+// batchLen returns the number of leading events fitting in one request.
+func batchLen(events []event) int {
+	size := 0
+	first := time.UnixMilli(*events[0].event.Timestamp)
+	for i, e := range events {
+		size += len(*e.event.Message) + eventOverhead
+		if i == maxBatchEvents || i > 0 && size > maxBatchBytes ||
+			time.UnixMilli(*e.event.Timestamp).Sub(first) >= maxBatchSpan {
+			return i
+		}
+	}
+	return len(events)
+}
+
+func (p *Publisher) put(ctx context.Context, stream string, batch []event) error {
+	events := make([]types.InputLogEvent, len(batch))
+	for i, e := range batch {
+		events[i] = e.event
+	}
+	backoff := 200 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		out, err := p.client.PutLogEvents(ctx, &cloudwatchlogs.PutLogEventsInput{
+			LogGroupName:  aws.String(p.group),
+			LogStreamName: aws.String(stream),
+			LogEvents:     events,
+			SequenceToken: p.tokens[stream],
+		})
+		if err == nil {
+			p.tokens[stream] = out.NextSequenceToken
+			return nil
+		}
+
+		var invalidToken *types.InvalidSequenceTokenException
+		var throttled *types.ThrottlingException
+		var unavailable *types.ServiceUnavailableException
+		switch {
+		case errors.As(err, &invalidToken):
+			p.tokens[stream] = invalidToken.ExpectedSequenceToken
+		case errors.As(err, &throttled), errors.As(err, &unavailable):
+		default:
+			return err
+		}
+		if attempt == maxRetries {
+			return err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// ensureStream creates the log group and stream on first use when the
+// Publisher creates them.
+func (p *Publisher) ensureStream(ctx context.Context, stream string) error {
+	if !p.create || p.created[stream] {
+		return nil
+	}
+	var exists *types.ResourceAlreadyExistsException
+	_, err := p.client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(p.group),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		return err
+	}
+	_, err = p.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(p.group),
+		LogStreamName: aws.String(stream),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		return err
+	}
+	p.created[stream] = true
+	return nil
+}
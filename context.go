@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// ContextKey is the echo.Context key the request-scoped logger is stored
+// under when Config.ContextLogger is enabled.
+const ContextKey = "zapecho.logger"
+
+// FromContext returns the request-scoped logger stored by the middleware.
+// It falls back to zap.L() when the middleware did not store one.
+func FromContext(c echo.Context) *zap.Logger {
+	if l, ok := c.Get(ContextKey).(*zap.Logger); ok && l != nil {
+		return l
+	}
+	return zap.L()
+}
+
+// contextLogger returns a child of l carrying the request metadata known
+// before the handler runs. Empty remoteIP and id are omitted.
+func contextLogger(l *zap.Logger, c echo.Context, names FieldNames, remoteIP, id string) *zap.Logger {
+	req := c.Request()
+	fields := []zap.Field{
+		zap.String(names.Method, req.Method),
+		zap.String(names.Route, c.Path()),
+	}
+	if remoteIP != "" {
+		fields = append(fields, zap.String(names.RemoteIP, remoteIP))
+	}
+	if id != "" {
+		fields = append(fields, zap.String(names.RequestID, id))
+	}
+
+	return l.With(fields...)
+}
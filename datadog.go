@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewDatadogEncoderConfig returns a zapcore.EncoderConfig using Datadog's
+// reserved attributes, so the default status and date remappers apply.
+func NewDatadogEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		MessageKey:     "message",
+		LevelKey:       "status",
+		TimeKey:        "timestamp",
+		NameKey:        "logger.name",
+		CallerKey:      "logger.caller",
+		StacktraceKey:  "error.stack",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// NewDatadogConfig returns a logging configuration for Datadog.
+func NewDatadogConfig(lv zap.AtomicLevel) zap.Config {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = lv
+	cfg.EncoderConfig = NewDatadogEncoderConfig()
+
+	return cfg
+}
+
+// NewDatadogMiddlewareConfig returns a middleware config logging access
+// entries with Datadog standard attributes and dd.trace_id/dd.span_id for
+// log-trace correlation.
+func NewDatadogMiddlewareConfig(lv zap.AtomicLevel) Config {
+	logger, err := NewDatadogConfig(lv).Build()
+	if err != nil {
+		panic(fmt.Errorf("logging.NewDatadogMiddlewareConfig: %v", err))
+	}
+
+	c := DefaultConfig
+	c.Logger = logger
+	c.FieldNames = FieldNames{
+		RemoteIP:      "network.client.ip",
+		Host:          "http.url_details.host",
+		Status:        "http.status_code",
+		Size:          "network.bytes_written",
+		BytesIn:       "network.bytes_read",
+		UserAgent:     "http.useragent",
+		RequestID:     "http.request_id",
+		Method:        "http.method",
+		URI:           "http.url",
+		Referer:       "http.referer",
+		Path:          "http.url_details.path",
+		Route:         "http.route",
+		ScaledLatency: "duration",
+		Error:         "error.message",
+		ErrorType:     "error.kind",
+	}
+	c.DisableLatency = true
+	c.LatencyUnit = time.Nanosecond
+	c.LogPath = true
+	c.TraceFormatter = datadogTraceFields
+
+	return c
+}
+
+// datadogTraceFields logs the lower 64 bits of the trace and span IDs as
+// decimals, the format Datadog correlates on.
+func datadogTraceFields(tc TraceContext) []zapcore.Field {
+	traceID := tc.TraceID
+	if len(traceID) > 16 {
+		traceID = traceID[len(traceID)-16:]
+	}
+	t, err := strconv.ParseUint(traceID, 16, 64)
+	if err != nil {
+		return nil
+	}
+	s, err := strconv.ParseUint(tc.SpanID, 16, 64)
+	if err != nil {
+		return nil
+	}
+	return []zapcore.Field{
+		zap.String("dd.trace_id", strconv.FormatUint(t, 10)),
+		zap.String("dd.span_id", strconv.FormatUint(s, 10)),
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HeaderDebugLog is a conventional Config.DebugHeader.
+const HeaderDebugLog = "X-Debug-Log"
+
+// debugMiddleware logs the requests carrying the debug header with a
+// verbose copy of config and the others with config.
+func debugMiddleware(config Config) (echo.MiddlewareFunc, func() error) {
+	if config.Logger == nil {
+		config.Logger = defaultLogger(config)
+	}
+	header, secret := config.DebugHeader, []byte(config.DebugSecret)
+	config.DebugHeader, config.DebugSecret = "", ""
+
+	mw, closeMW := ZapMiddlewareWithClose(config)
+	// The loggers share their cores, syncing one is enough.
+	verboseConfig := config.verbose()
+	verboseConfig.SyncInterval = 0
+	verboseMW := ZapMiddlewareWithConfig(verboseConfig)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		h, verbose := mw(next), verboseMW(next)
+		return func(c echo.Context) error {
+			v := c.Request().Header.Get(header)
+			if v == "" {
+				return h(c)
+			}
+			if len(secret) > 0 {
+				if subtle.ConstantTimeCompare([]byte(v), secret) == 1 {
+					return verbose(c)
+				}
+			} else if v == "1" || v == "true" {
+				return verbose(c)
+			}
+			return h(c)
+		}
+	}, closeMW
+}
+
+// verbose returns the config of debugged requests: every entry down to
+// Debug is logged, including the request start, and the optional request
+// fields and bodies are added.
+func (config Config) verbose() Config {
+	config.Logger = config.Logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return debugCore{core}
+	}))
+	config.LogRequestStart = true
+	config.LogQuery = true
+	config.LogProtocol = true
+	config.LogReferer = true
+	config.LogContentType = true
+	config.LogTLS = true
+	config.LogRequestBody = true
+	config.LogResponseBody = true
+	config.ResponseBodyMinStatus = http.StatusContinue
+	config.ErrorsOnly = false
+	config.SuccessSampling = 0
+	config.Probes = nil
+	config.QuietSources = nil
+	config.QuietPreflight = false
+	config.StaticAssets = nil
+	return config
+}
+
+// debugCore enables every level of the wrapped core.
+type debugCore struct {
+	zapcore.Core
+}
+
+func (debugCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+func (c debugCore) With(fields []zapcore.Field) zapcore.Core {
+	return debugCore{c.Core.With(fields)}
+}
+
+func (c debugCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, c)
+}
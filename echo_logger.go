@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// EchoLogger adapts a zap.Logger to the echo.Logger interface so it can be
+// assigned to echo.Echo.Logger.
+type EchoLogger struct {
+	base   *zap.Logger
+	logger *zap.Logger
+	sugar  *zap.SugaredLogger
+	level  zap.AtomicLevel
+	enc    zapcore.Encoder
+	prefix string
+	output io.Writer
+}
+
+var _ echo.Logger = (*EchoLogger)(nil)
+
+// NewEchoLogger returns an echo.Logger writing to the given logger. The level
+// must be the one the logger was built with for SetLevel to take effect.
+// SetOutput encodes entries as NewLogger does at that level.
+func NewEchoLogger(logger *zap.Logger, lv zap.AtomicLevel) *EchoLogger {
+	c := newConfig(lv)
+	return NewEchoLoggerWithEncoder(logger, lv, newEncoder(&c))
+}
+
+// NewEchoLoggerWithEncoder is NewEchoLogger for a logger built with
+// another encoder, used by SetOutput.
+func NewEchoLoggerWithEncoder(logger *zap.Logger, lv zap.AtomicLevel, enc zapcore.Encoder) *EchoLogger {
+	l := &EchoLogger{
+		base:   logger.WithOptions(zap.AddCallerSkip(1)),
+		level:  lv,
+		enc:    enc,
+		output: os.Stderr,
+	}
+	l.setLogger()
+	return l
+}
+
+// setLogger derives the logger from the base logger and prefix.
+func (l *EchoLogger) setLogger() {
+	l.logger = l.base
+	if l.prefix != "" {
+		l.logger = l.base.Named(l.prefix)
+	}
+	l.sugar = l.logger.Sugar()
+}
+
+// Output returns the writer set by SetOutput, os.Stderr by default.
+func (l *EchoLogger) Output() io.Writer {
+	return l.output
+}
+
+// SetOutput replaces the logger's core with one writing to w, keeping the
+// encoder and level.
+func (l *EchoLogger) SetOutput(w io.Writer) {
+	l.output = w
+	core := zapcore.NewCore(l.enc.Clone(), zapcore.AddSync(w), l.level)
+	l.base = l.base.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return core
+	}))
+	l.setLogger()
+}
+
+// Prefix returns the logger name set by SetPrefix.
+func (l *EchoLogger) Prefix() string {
+	return l.prefix
+}
+
+// SetPrefix names the logger after p, replacing the previous prefix.
+func (l *EchoLogger) SetPrefix(p string) {
+	l.prefix = p
+	l.setLogger()
+}
+
+// Level returns the current level.
+func (l *EchoLogger) Level() log.Lvl {
+	switch l.level.Level() {
+	case zapcore.DebugLevel:
+		return log.DEBUG
+	case zapcore.InfoLevel:
+		return log.INFO
+	case zapcore.WarnLevel:
+		return log.WARN
+	case zapcore.ErrorLevel:
+		return log.ERROR
+	default:
+		return log.OFF
+	}
+}
+
+// SetLevel sets the level.
+func (l *EchoLogger) SetLevel(v log.Lvl) {
+	switch v {
+	case log.DEBUG:
+		l.level.SetLevel(zapcore.DebugLevel)
+	case log.INFO:
+		l.level.SetLevel(zapcore.InfoLevel)
+	case log.WARN:
+		l.level.SetLevel(zapcore.WarnLevel)
+	case log.ERROR:
+		l.level.SetLevel(zapcore.ErrorLevel)
+	case log.OFF:
+		// Above every level, Fatal entries included.
+		l.level.SetLevel(zapcore.FatalLevel + 1)
+	}
+}
+
+// SetHeader is a no-op, the entry layout is defined by the zap encoder.
+func (l *EchoLogger) SetHeader(h string) {}
+
+func (l *EchoLogger) Print(i ...interface{}) {
+	l.sugar.Info(i...)
+}
+
+func (l *EchoLogger) Printf(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+func (l *EchoLogger) Printj(j log.JSON) {
+	l.sugar.Infow("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Debug(i ...interface{}) {
+	l.sugar.Debug(i...)
+}
+
+func (l *EchoLogger) Debugf(format string, args ...interface{}) {
+	l.sugar.Debugf(format, args...)
+}
+
+func (l *EchoLogger) Debugj(j log.JSON) {
+	l.sugar.Debugw("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Info(i ...interface{}) {
+	l.sugar.Info(i...)
+}
+
+func (l *EchoLogger) Infof(format string, args ...interface{}) {
+	l.sugar.Infof(format, args...)
+}
+
+func (l *EchoLogger) Infoj(j log.JSON) {
+	l.sugar.Infow("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Warn(i ...interface{}) {
+	l.sugar.Warn(i...)
+}
+
+func (l *EchoLogger) Warnf(format string, args ...interface{}) {
+	l.sugar.Warnf(format, args...)
+}
+
+func (l *EchoLogger) Warnj(j log.JSON) {
+	l.sugar.Warnw("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Error(i ...interface{}) {
+	l.sugar.Error(i...)
+}
+
+func (l *EchoLogger) Errorf(format string, args ...interface{}) {
+	l.sugar.Errorf(format, args...)
+}
+
+func (l *EchoLogger) Errorj(j log.JSON) {
+	l.sugar.Errorw("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Fatal(i ...interface{}) {
+	l.sugar.Fatal(i...)
+}
+
+func (l *EchoLogger) Fatalj(j log.JSON) {
+	l.sugar.Fatalw("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Fatalf(format string, args ...interface{}) {
+	l.sugar.Fatalf(format, args...)
+}
+
+func (l *EchoLogger) Panic(i ...interface{}) {
+	l.sugar.Panic(i...)
+}
+
+func (l *EchoLogger) Panicj(j log.JSON) {
+	l.sugar.Panicw("", jsonKeysAndValues(j)...)
+}
+
+func (l *EchoLogger) Panicf(format string, args ...interface{}) {
+	l.sugar.Panicf(format, args...)
+}
+
+// jsonKeysAndValues flattens j into sorted key-value pairs.
+func jsonKeysAndValues(j log.JSON) []interface{} {
+	keys := make([]string, 0, len(j))
+	for k := range j {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	kv := make([]interface{}, 0, len(j)*2)
+	for _, k := range keys {
+		kv = append(kv, k, j[k])
+	}
+	return kv
+}
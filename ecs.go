@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ECSVersion is the Elastic Common Schema version the ECS preset follows.
+const ECSVersion = "8.11.0"
+
+// NewECSEncoderConfig returns a zapcore.EncoderConfig producing Elastic
+// Common Schema log fields.
+func NewECSEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		MessageKey:     "message",
+		LevelKey:       "log.level",
+		TimeKey:        "@timestamp",
+		NameKey:        "log.logger",
+		CallerKey:      "log.origin.file.name",
+		StacktraceKey:  "error.stack_trace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.NanosDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// NewECSConfig returns a logging configuration producing Elastic Common
+// Schema documents.
+func NewECSConfig(lv zap.AtomicLevel) zap.Config {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = lv
+	cfg.EncoderConfig = NewECSEncoderConfig()
+	cfg.InitialFields = map[string]interface{}{"ecs.version": ECSVersion}
+
+	return cfg
+}
+
+// NewECSMiddlewareConfig returns a middleware config logging access entries
+// with Elastic Common Schema field names, so they can be used by Kibana's
+// built-in dashboards.
+func NewECSMiddlewareConfig(lv zap.AtomicLevel) Config {
+	logger, err := NewECSConfig(lv).Build()
+	if err != nil {
+		panic(fmt.Errorf("logging.NewECSMiddlewareConfig: %v", err))
+	}
+
+	c := DefaultConfig
+	c.Logger = logger
+	c.FieldNames = FieldNames{
+		RemoteIP:  "source.ip",
+		Host:      "url.domain",
+		Status:    "http.response.status_code",
+		Size:      "http.response.body.bytes",
+		BytesIn:   "http.request.body.bytes",
+		UserAgent: "user_agent.original",
+		RequestID: "http.request.id",
+		Method:    "http.request.method",
+		URI:       "url.original",
+		Referer:   "http.request.referrer",
+
+		RequestContentType:  "http.request.mime_type",
+		ResponseContentType: "http.response.mime_type",
+		TLSVersion:          "tls.version",
+		TLSVersionProtocol:  "tls.version_protocol",
+		TLSCipher:           "tls.cipher",
+		TLSServerName:       "tls.client.server_name",
+
+		ClientCertSubject:     "tls.client.subject",
+		ClientCertIssuer:      "tls.client.issuer",
+		ClientCertSerial:      "tls.client.x509.serial_number",
+		ClientCertFingerprint: "tls.client.hash.sha256",
+		Path:                  "url.path",
+		Route:                 "http.route",
+		ScaledLatency:         "event.duration",
+		TraceID:               "trace.id",
+		SpanID:                "span.id",
+		TraceFlags:            "trace.flags",
+
+		Error:         "error.message",
+		ErrorType:     "error.type",
+		ErrorCode:     "error.code",
+		ErrorMessage:  "error.http_message",
+		ErrorInternal: "error.internal",
+	}
+	c.DisableLatency = true
+	c.LatencyUnit = time.Nanosecond
+	c.LogPath = true
+
+	return c
+}
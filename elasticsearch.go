@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Elasticsearch configures an Elasticsearch bulk indexing core.
+type Elasticsearch struct {
+	// URL is the base URL of the cluster, e.g. "http://es:9200".
+	URL string
+
+	// Index is the index, alias or data stream written to. With an ILM
+	// policy, it is the rollover write alias.
+	Index string
+
+	// Daily appends the UTC date of entries to Index, as in
+	// "access-logs-2024.01.31".
+	Daily bool
+
+	// DataStream indexes with the create operation data streams require.
+	DataStream bool
+
+	// Template is an index template installed as Index once, before the
+	// first batch, e.g. with mappings and an ILM policy.
+	// Optional. Default value nil, no template.
+	Template json.RawMessage
+
+	// Username and Password authenticate with basic auth, APIKey with an
+	// API key.
+	// Optional.
+	Username string
+	Password string
+	APIKey   string
+
+	// Encoder encodes the documents, which must be JSON objects.
+	// Optional. Default value a JSON encoder.
+	Encoder zapcore.Encoder
+
+	// Client sends the requests.
+	// Optional. Default value a client with a 30s timeout.
+	Client *http.Client
+
+	// MaxRetries is the number of times documents rejected with 429 or 5xx
+	// are retried, with exponential backoff from 100ms.
+	// Optional. Default value 3.
+	MaxRetries int
+
+	Batching Batching
+}
+
+// NewElasticsearchCore returns a core indexing entries through the _bulk
+// API in batches. Documents rejected as invalid are dropped.
+func NewElasticsearchCore(es Elasticsearch, enab zapcore.LevelEnabler) *BatchCore {
+	if es.Client == nil {
+		es.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if es.MaxRetries <= 0 {
+		es.MaxRetries = 3
+	}
+	base := strings.TrimSuffix(es.URL, "/")
+	op := "index"
+	if es.DataStream {
+		op = "create"
+	}
+
+	encodeDoc := encodeJSON(es.Encoder)
+	encode := func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		e, err := encodeDoc(ent, fields)
+		e.key = es.Index
+		if es.Daily {
+			e.key += "-" + ent.Time.UTC().Format("2006.01.02")
+		}
+		return e, err
+	}
+
+	// Sends are serialized, so the template needs no locking.
+	templateInstalled := es.Template == nil
+	send := func(batch []batchEntry) error {
+		if !templateInstalled {
+			if err := es.putTemplate(base); err != nil {
+				return err
+			}
+			templateInstalled = true
+		}
+
+		backoff := 100 * time.Millisecond
+		for attempt := 0; ; attempt++ {
+			retry, err := es.bulk(base, op, batch)
+			if err != nil || len(retry) == 0 {
+				return err
+			}
+			if attempt == es.MaxRetries {
+				return permanentError{fmt.Errorf("logger: elasticsearch rejected %d documents after %d retries", len(retry), attempt)}
+			}
+			time.Sleep(backoff)
+			backoff *= 2
+			batch = retry
+		}
+	}
+
+	return newBatchCore(enab, es.Batching, encode, send)
+}
+
+// bulk indexes batch and returns the entries to retry.
+func (es Elasticsearch) bulk(base, op string, batch []batchEntry) ([]batchEntry, error) {
+	var body bytes.Buffer
+	for _, e := range batch {
+		fmt.Fprintf(&body, `{%q:{"_index":%q}}`+"\n", op, e.key)
+		body.Write(e.data)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, base+"/_bulk", &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	es.authorize(req)
+
+	res, err := es.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return nil, statusError(req, res)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if !result.Errors {
+		return nil, nil
+	}
+	if len(result.Items) != len(batch) {
+		return nil, errors.New("logger: elasticsearch bulk: unexpected number of items")
+	}
+
+	var retry []batchEntry
+	for i, item := range result.Items {
+		for _, r := range item {
+			if r.Status == http.StatusTooManyRequests || r.Status >= 500 {
+				retry = append(retry, batch[i])
+			}
+		}
+	}
+	return retry, nil
+}
+
+func (es Elasticsearch) putTemplate(base string) error {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut,
+		base+"/_index_template/"+es.Index, bytes.NewReader(es.Template))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	es.authorize(req)
+	return doHTTP(es.Client, req)
+}
+
+func (es Elasticsearch) authorize(req *http.Request) {
+	switch {
+	case es.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+es.APIKey)
+	case es.Username != "":
+		req.SetBasicAuth(es.Username, es.Password)
+	}
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Environment variables NewLoggerFromEnv reads.
+const (
+	// EnvLogLevel is the minimum level, "debug", "info", "warn", "error",
+	// "dpanic", "panic" or "fatal". Default "info".
+	EnvLogLevel = "LOG_LEVEL"
+	// EnvLogFormat is the encoding, "json" or "console". Default "console"
+	// at debug level, "json" otherwise.
+	EnvLogFormat = "LOG_FORMAT"
+	// EnvLogOutput is a comma-separated list of output paths, such as
+	// "stdout" or "/var/log/app.log". Default "stderr".
+	EnvLogOutput = "LOG_OUTPUT"
+	// EnvLogSampling is the sampling of entries, "initial,thereafter" as
+	// WithSampling takes, or "off". Default "100,100", off at debug level.
+	EnvLogSampling = "LOG_SAMPLING"
+	// EnvLogCaller enables or disables the caller annotation, as a boolean.
+	// Default off at debug level, on otherwise.
+	EnvLogCaller = "LOG_CALLER"
+	// EnvLogStacktraceLevel is the level from which entries record a
+	// stacktrace. Default "warn" at debug level, "error" otherwise.
+	EnvLogStacktraceLevel = "LOG_STACKTRACE_LEVEL"
+)
+
+// NewLoggerFromEnv returns the new zap.Logger of NewLoggerWithOptions
+// configured by opts and the LOG_* environment variables, which take
+// precedence, along with its level for LevelHandler or Config.Level.
+// Unset variables keep their default.
+func NewLoggerFromEnv(opts ...Option) (*zap.Logger, zap.AtomicLevel, error) {
+	lv := zap.NewAtomicLevel()
+	if v := os.Getenv(EnvLogLevel); v != "" {
+		l, err := zapcore.ParseLevel(v)
+		if err != nil {
+			return nil, lv, fmt.Errorf("logger: %s: %v", EnvLogLevel, err)
+		}
+		lv.SetLevel(l)
+	}
+
+	envOpts, err := envOptions()
+	if err != nil {
+		return nil, lv, err
+	}
+
+	logger, err := NewLoggerFromConfig(newConfig(lv), append(opts[:len(opts):len(opts)], envOpts...)...)
+	if err != nil {
+		return nil, lv, err
+	}
+	return logger, lv, nil
+}
+
+// envOptions returns the options set by the environment variables.
+func envOptions() ([]Option, error) {
+	var opts []Option
+	if v := os.Getenv(EnvLogFormat); v != "" {
+		if v != "json" && v != "console" {
+			return nil, fmt.Errorf("logger: %s: unknown format %q", EnvLogFormat, v)
+		}
+		opts = append(opts, WithEncoding(v))
+	}
+	if v := os.Getenv(EnvLogOutput); v != "" {
+		paths := strings.Split(v, ",")
+		for i := range paths {
+			paths[i] = strings.TrimSpace(paths[i])
+		}
+		opts = append(opts, WithOutputPaths(paths...))
+	}
+	if v := os.Getenv(EnvLogSampling); v != "" {
+		opt, err := parseSampling(v)
+		if err != nil {
+			return nil, fmt.Errorf("logger: %s: %v", EnvLogSampling, err)
+		}
+		opts = append(opts, opt)
+	}
+	if v := os.Getenv(EnvLogCaller); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("logger: %s: %v", EnvLogCaller, err)
+		}
+		opts = append(opts, WithCallerEnabled(enabled))
+	}
+	if v := os.Getenv(EnvLogStacktraceLevel); v != "" {
+		l, err := zapcore.ParseLevel(v)
+		if err != nil {
+			return nil, fmt.Errorf("logger: %s: %v", EnvLogStacktraceLevel, err)
+		}
+		opts = append(opts, WithStacktraceLevel(l))
+	}
+	return opts, nil
+}
+
+// parseSampling parses "off" or "initial,thereafter".
+func parseSampling(v string) (Option, error) {
+	if v == "off" {
+		return WithoutSampling(), nil
+	}
+	first, second, ok := strings.Cut(v, ",")
+	if !ok {
+		return nil, fmt.Errorf("invalid sampling %q", v)
+	}
+	initial, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil || initial < 0 {
+		return nil, fmt.Errorf("invalid sampling %q", v)
+	}
+	thereafter, err := strconv.Atoi(strings.TrimSpace(second))
+	if err != nil || thereafter < 0 {
+		return nil, fmt.Errorf("invalid sampling %q", v)
+	}
+	return WithSampling(initial, thereafter), nil
+}
@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// ServerError is a request answered with a 5xx status, passed to
+// Config.OnServerError.
+type ServerError struct {
+	Status int
+	// Err is the handler error, nil when the handler wrote the status
+	// itself.
+	Err error
+	// Panic reports whether Err is a recovered panic.
+	Panic   bool
+	Message string
+	// Fields are the fields of the access log entry.
+	Fields []zapcore.Field
+}
+
+// errorFields returns the handler error and its type.
+func errorFields(err error, names FieldNames) []zapcore.Field {
+	if err == nil {
+		return nil
+	}
+	return []zapcore.Field{
+		zap.NamedError(names.Error, err),
+		zap.String(names.ErrorType, reflect.TypeOf(err).String()),
+	}
+}
+
+// httpErrorFields returns the code, message and internal error of an
+// *echo.HTTPError in err's chain.
+func httpErrorFields(err error, names FieldNames) []zapcore.Field {
+	var he *echo.HTTPError
+	if !errors.As(err, &he) {
+		return nil
+	}
+	fields := []zapcore.Field{
+		zap.Int(names.ErrorCode, he.Code),
+		zap.Any(names.ErrorMessage, he.Message),
+	}
+	if he.Internal != nil {
+		fields = append(fields, zap.NamedError(names.ErrorInternal, he.Internal))
+	}
+	return fields
+}
+
+// errorStack returns the stack trace recorded by an error in err's chain
+// with a StackTrace method, such as the errors of github.com/pkg/errors,
+// formatted with %+v.
+func errorStack(err error) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		m := reflect.ValueOf(err).MethodByName("StackTrace")
+		if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+			continue
+		}
+		return fmt.Sprintf("%+v", m.Call(nil)[0].Interface()), true
+	}
+	return "", false
+}
@@ -0,0 +1,338 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Failover configures a failover core.
+type Failover struct {
+	// Path is the local file entries are written to while the primary
+	// core fails.
+	Path string
+
+	// Threshold is the number of consecutive failed writes after which
+	// the primary core is considered down.
+	// Optional. Default value 3.
+	Threshold int
+
+	// RetryInterval is the time between attempts to write to a primary
+	// core that is down.
+	// Optional. Default value 10s.
+	RetryInterval time.Duration
+
+	// Replay writes the entries of the file to the primary core once it
+	// recovers, and then truncates the file.
+	Replay bool
+}
+
+// healthReporter is implemented by cores writing asynchronously, such as
+// BatchCore, whose Write does not report send failures.
+type healthReporter interface {
+	Healthy() bool
+}
+
+// failoverKeys are the keys of the fallback file encoder, read back by
+// replay.
+var failoverKeys = zapcore.EncoderConfig{
+	TimeKey:        "ts",
+	LevelKey:       "level",
+	NameKey:        "logger",
+	CallerKey:      "caller",
+	MessageKey:     "msg",
+	StacktraceKey:  "stacktrace",
+	LineEnding:     zapcore.DefaultLineEnding,
+	EncodeLevel:    zapcore.LowercaseLevelEncoder,
+	EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+	EncodeDuration: zapcore.StringDurationEncoder,
+	EncodeCaller:   zapcore.ShortCallerEncoder,
+}
+
+// FailoverCore writes to a primary core, such as a Loki or Kafka core,
+// and to a local file while the primary fails: after Threshold
+// consecutive write errors, or while a BatchCore primary's sends fail.
+type FailoverCore struct {
+	primary  zapcore.Core
+	fallback zapcore.Core
+	state    *failoverState
+}
+
+type failoverState struct {
+	config Failover
+	root   zapcore.Core
+	disk   *diskBuffer
+
+	mu        sync.Mutex
+	failures  int
+	down      bool
+	lastRetry time.Time
+}
+
+// NewFailoverCore returns a core failing over from primary to the file of
+// f.
+func NewFailoverCore(primary zapcore.Core, f Failover) (*FailoverCore, error) {
+	if f.Threshold <= 0 {
+		f.Threshold = 3
+	}
+	if f.RetryInterval <= 0 {
+		f.RetryInterval = 10 * time.Second
+	}
+	disk, err := openDiskBuffer(f.Path, primary)
+	if err != nil {
+		return nil, err
+	}
+	return &FailoverCore{
+		primary:  primary,
+		fallback: disk.core,
+		state:    &failoverState{config: f, root: primary, disk: disk},
+	}, nil
+}
+
+func (c *FailoverCore) Enabled(l zapcore.Level) bool {
+	return c.primary.Enabled(l)
+}
+
+func (c *FailoverCore) With(fields []zapcore.Field) zapcore.Core {
+	return &FailoverCore{
+		primary:  c.primary.With(fields),
+		fallback: c.fallback.With(fields),
+		state:    c.state,
+	}
+}
+
+func (c *FailoverCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *FailoverCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	s := c.state
+	if !s.usePrimary() {
+		return c.fallback.Write(ent, fields)
+	}
+	err := c.primary.Write(ent, fields)
+	if hr, ok := c.primary.(healthReporter); ok && err == nil && !hr.Healthy() {
+		// The entry is buffered by the primary, it is not lost.
+		s.failed(false)
+		return nil
+	}
+	if err != nil {
+		if s.failed(true) {
+			return c.fallback.Write(ent, fields)
+		}
+		return err
+	}
+	s.succeeded()
+	return nil
+}
+
+func (c *FailoverCore) Sync() error {
+	c.fallback.Sync()
+	return c.primary.Sync()
+}
+
+// Close closes the fallback file.
+func (c *FailoverCore) Close() error {
+	return c.state.disk.close()
+}
+
+// usePrimary reports whether to write to the primary core, which is when
+// it is up or due a retry.
+func (s *failoverState) usePrimary() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.down {
+		return true
+	}
+	if time.Since(s.lastRetry) >= s.config.RetryInterval {
+		s.lastRetry = time.Now()
+		return true
+	}
+	return false
+}
+
+// failed records a failure, counted towards Threshold unless immediate,
+// and reports whether the primary is down.
+func (s *failoverState) failed(counted bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures++
+	if !counted || s.failures >= s.config.Threshold {
+		if !s.down {
+			s.down, s.lastRetry = true, time.Now()
+		}
+	}
+	return s.down
+}
+
+func (s *failoverState) succeeded() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recovered := s.down
+	s.failures, s.down = 0, false
+	if recovered && s.config.Replay {
+		s.disk.replay(s.root)
+	}
+}
+
+// diskBuffer is a local file entries are written to while a sink is
+// unhealthy, in a format replay can read back.
+type diskBuffer struct {
+	path string
+	core zapcore.Core
+
+	// mu guards file, held by writes and across a replay.
+	mu   sync.Mutex
+	file *os.File
+
+	replaying atomic.Bool
+}
+
+func openDiskBuffer(path string, enab zapcore.LevelEnabler) (*diskBuffer, error) {
+	file, err := openSpool(path)
+	if err != nil {
+		return nil, err
+	}
+	d := &diskBuffer{path: path, file: file}
+	d.core = zapcore.NewCore(zapcore.NewJSONEncoder(failoverKeys), diskWriter{d}, enab)
+	return d, nil
+}
+
+func openSpool(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+}
+
+func (d *diskBuffer) close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.file.Close()
+}
+
+// diskWriter writes to the file of a diskBuffer.
+type diskWriter struct {
+	d *diskBuffer
+}
+
+func (w diskWriter) Write(p []byte) (int, error) {
+	w.d.mu.Lock()
+	defer w.d.mu.Unlock()
+	return w.d.file.Write(p)
+}
+
+func (w diskWriter) Sync() error {
+	w.d.mu.Lock()
+	defer w.d.mu.Unlock()
+	return w.d.file.Sync()
+}
+
+// replay writes the entries of the file to root in the background, unless
+// a replay is running, and truncates the file.
+func (d *diskBuffer) replay(root zapcore.Core) {
+	if d.replaying.CompareAndSwap(false, true) {
+		go func() {
+			defer d.replaying.Store(false)
+			d.replayTo(root)
+		}()
+	}
+}
+
+// replayTo writes the entries of the file to root. Entries are not
+// spooled meanwhile, so none is lost by the truncation. When root fails
+// midway the sent entries are removed, to not be sent again.
+func (d *diskBuffer) replayTo(root zapcore.Core) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.Open(d.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), 16<<20)
+	var sent int64
+	for sc.Scan() {
+		line := sc.Bytes()
+		if ent, fields, ok := decodeFailoverLine(line); ok {
+			if err := root.Write(ent, fields); err != nil {
+				// Down again, keep the rest for the next recovery.
+				d.compactLocked(f, sent)
+				return
+			}
+		}
+		sent += int64(len(line)) + 1
+	}
+	if sc.Err() != nil {
+		d.compactLocked(f, sent)
+		return
+	}
+	root.Sync()
+	d.file.Truncate(0)
+}
+
+// compactLocked replaces the file by its content past offset.
+func (d *diskBuffer) compactLocked(f *os.File, offset int64) {
+	if offset == 0 {
+		return
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+	tmp := d.path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return
+	}
+	_, err = io.Copy(out, f)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, d.path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return
+	}
+	if file, err := openSpool(d.path); err == nil {
+		d.file.Close()
+		d.file = file
+	}
+}
+
+// decodeFailoverLine decodes an entry of the fallback file.
+func decodeFailoverLine(line []byte) (zapcore.Entry, []zapcore.Field, bool) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(line, &m); err != nil {
+		return zapcore.Entry{}, nil, false
+	}
+	var ent zapcore.Entry
+	if ts, ok := m[failoverKeys.TimeKey].(string); ok {
+		ent.Time, _ = time.Parse(time.RFC3339Nano, ts)
+	}
+	if lvl, ok := m[failoverKeys.LevelKey].(string); ok {
+		ent.Level.UnmarshalText([]byte(lvl))
+	}
+	ent.Message, _ = m[failoverKeys.MessageKey].(string)
+	ent.LoggerName, _ = m[failoverKeys.NameKey].(string)
+	ent.Stack, _ = m[failoverKeys.StacktraceKey].(string)
+	for _, k := range []string{failoverKeys.TimeKey, failoverKeys.LevelKey, failoverKeys.MessageKey,
+		failoverKeys.NameKey, failoverKeys.StacktraceKey, failoverKeys.CallerKey} {
+		delete(m, k)
+	}
+
+	fields := make([]zapcore.Field, 0, len(m))
+	for k, v := range m {
+		fields = append(fields, zap.Any(k, v))
+	}
+	return ent, fields, true
+}
@@ -0,0 +1,181 @@
+package logger
+
+// FieldNames defines the keys access log fields are written under.
+type FieldNames struct {
+	RemoteIP  string
+	Latency   string
+	Host      string
+	Request   string
+	Status    string
+	Size      string
+	BytesIn   string
+	UserAgent string
+	RequestID string
+	Method    string
+	Route     string
+	URI       string
+	Path      string
+	Query     string
+	AuthUser  string
+	Referer   string
+	Protocol  string
+
+	ForwardedFor string
+	PeerAddress  string
+	// PeerPort, when set, logs the port of the peer address apart.
+	PeerPort string
+	// HostPort, when set, logs the port of Host apart.
+	HostPort string
+
+	RequestContentType  string
+	ResponseContentType string
+
+	TLSVersion    string
+	TLSCipher     string
+	TLSServerName string
+	// TLSVersionProtocol, when set, logs the protocol of TLSVersion, "tls",
+	// with the version reduced to its number, e.g. "1.3".
+	TLSVersionProtocol string
+
+	ClientCertSubject     string
+	ClientCertIssuer      string
+	ClientCertSerial      string
+	ClientCertFingerprint string
+	Slow                  string
+	Bot                   string
+
+	Panic      string
+	Stacktrace string
+
+	Error         string
+	ErrorType     string
+	ErrorCode     string
+	ErrorMessage  string
+	ErrorInternal string
+
+	RequestBody     string
+	ResponseBody    string
+	RequestHeaders  string
+	ResponseHeaders string
+
+	TraceID    string
+	SpanID     string
+	TraceFlags string
+
+	// ScaledLatency is the key of the Config.LatencyUnit field. Defaults to
+	// Latency suffixed with the unit, e.g. "latency_ms".
+	ScaledLatency string
+}
+
+// DefaultFieldNames are the keys used for fields left empty in
+// Config.FieldNames.
+var DefaultFieldNames = FieldNames{
+	RemoteIP:  "remote_ip",
+	Latency:   "latency",
+	Host:      "host",
+	Request:   "request",
+	Status:    "status",
+	Size:      "size",
+	BytesIn:   "bytes_in",
+	UserAgent: "user_agent",
+	RequestID: "request_id",
+	Method:    "method",
+	Route:     "route",
+	URI:       "uri",
+	Path:      "path",
+	Query:     "query",
+	AuthUser:  "auth_user",
+	Referer:   "referer",
+	Protocol:  "proto",
+
+	ForwardedFor: "forwarded_for",
+	PeerAddress:  "peer_addr",
+
+	RequestContentType:  "request_content_type",
+	ResponseContentType: "response_content_type",
+
+	TLSVersion:    "tls_version",
+	TLSCipher:     "tls_cipher",
+	TLSServerName: "tls_server_name",
+
+	ClientCertSubject:     "client_cert_subject",
+	ClientCertIssuer:      "client_cert_issuer",
+	ClientCertSerial:      "client_cert_serial",
+	ClientCertFingerprint: "client_cert_fingerprint",
+	Slow:                  "slow",
+	Bot:                   "is_bot",
+
+	Panic:      "panic",
+	Stacktrace: "stacktrace",
+
+	Error:         "error",
+	ErrorType:     "error_type",
+	ErrorCode:     "error_code",
+	ErrorMessage:  "error_message",
+	ErrorInternal: "error_internal",
+
+	RequestBody:     "request_body",
+	ResponseBody:    "response_body",
+	RequestHeaders:  "request_headers",
+	ResponseHeaders: "response_headers",
+
+	TraceID:    "trace_id",
+	SpanID:     "span_id",
+	TraceFlags: "trace_flags",
+}
+
+// withDefaults returns n with empty names replaced by DefaultFieldNames.
+func (n FieldNames) withDefaults() FieldNames {
+	d := DefaultFieldNames
+	setDefault(&n.RemoteIP, d.RemoteIP)
+	setDefault(&n.Latency, d.Latency)
+	setDefault(&n.Host, d.Host)
+	setDefault(&n.Request, d.Request)
+	setDefault(&n.Status, d.Status)
+	setDefault(&n.Size, d.Size)
+	setDefault(&n.BytesIn, d.BytesIn)
+	setDefault(&n.UserAgent, d.UserAgent)
+	setDefault(&n.RequestID, d.RequestID)
+	setDefault(&n.Method, d.Method)
+	setDefault(&n.Route, d.Route)
+	setDefault(&n.URI, d.URI)
+	setDefault(&n.Path, d.Path)
+	setDefault(&n.Query, d.Query)
+	setDefault(&n.AuthUser, d.AuthUser)
+	setDefault(&n.Referer, d.Referer)
+	setDefault(&n.Protocol, d.Protocol)
+	setDefault(&n.ForwardedFor, d.ForwardedFor)
+	setDefault(&n.PeerAddress, d.PeerAddress)
+	setDefault(&n.RequestContentType, d.RequestContentType)
+	setDefault(&n.ResponseContentType, d.ResponseContentType)
+	setDefault(&n.TLSVersion, d.TLSVersion)
+	setDefault(&n.TLSCipher, d.TLSCipher)
+	setDefault(&n.TLSServerName, d.TLSServerName)
+	setDefault(&n.ClientCertSubject, d.ClientCertSubject)
+	setDefault(&n.ClientCertIssuer, d.ClientCertIssuer)
+	setDefault(&n.ClientCertSerial, d.ClientCertSerial)
+	setDefault(&n.ClientCertFingerprint, d.ClientCertFingerprint)
+	setDefault(&n.Slow, d.Slow)
+	setDefault(&n.Bot, d.Bot)
+	setDefault(&n.Panic, d.Panic)
+	setDefault(&n.Stacktrace, d.Stacktrace)
+	setDefault(&n.Error, d.Error)
+	setDefault(&n.ErrorType, d.ErrorType)
+	setDefault(&n.ErrorCode, d.ErrorCode)
+	setDefault(&n.ErrorMessage, d.ErrorMessage)
+	setDefault(&n.ErrorInternal, d.ErrorInternal)
+	setDefault(&n.RequestBody, d.RequestBody)
+	setDefault(&n.ResponseBody, d.ResponseBody)
+	setDefault(&n.RequestHeaders, d.RequestHeaders)
+	setDefault(&n.ResponseHeaders, d.ResponseHeaders)
+	setDefault(&n.TraceID, d.TraceID)
+	setDefault(&n.SpanID, d.SpanID)
+	setDefault(&n.TraceFlags, d.TraceFlags)
+	return n
+}
+
+func setDefault(s *string, v string) {
+	if *s == "" {
+		*s = v
+	}
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// RotatingFile configures a log file rotated by size and age.
+type RotatingFile struct {
+	// Filename is the file written to. Backups are kept in the same
+	// directory.
+	Filename string
+
+	// MaxSize is the size in megabytes a file is rotated at.
+	// Optional. Default value 100.
+	MaxSize int
+
+	// MaxAge is the number of days backups are kept, based on the time in
+	// their name.
+	// Optional. Default value 0, backups are not removed by age.
+	MaxAge int
+
+	// MaxBackups is the number of backups kept.
+	// Optional. Default value 0, every backup is kept.
+	MaxBackups int
+
+	// Compress gzips backups.
+	Compress bool
+
+	// LocalTime names backups with the local time instead of UTC.
+	LocalTime bool
+}
+
+// RotatingWriter is a zapcore.WriteSyncer writing to a RotatingFile. It
+// must be closed when the logger is no longer used.
+type RotatingWriter struct {
+	*lumberjack.Logger
+}
+
+// NewRotatingFile returns a writer for f, opened on the first write.
+func NewRotatingFile(f RotatingFile) *RotatingWriter {
+	return &RotatingWriter{&lumberjack.Logger{
+		Filename:   f.Filename,
+		MaxSize:    f.MaxSize,
+		MaxAge:     f.MaxAge,
+		MaxBackups: f.MaxBackups,
+		Compress:   f.Compress,
+		LocalTime:  f.LocalTime,
+	}}
+}
+
+// Sync implements zapcore.WriteSyncer. Writes are not buffered.
+func (w *RotatingWriter) Sync() error {
+	return nil
+}
+
+// WithRotatingFile tees a logger's entries to f, in the logger's encoding.
+func WithRotatingFile(f RotatingFile) Option {
+	return WithOutput(NewRotatingFile(f))
+}
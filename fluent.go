@@ -0,0 +1,301 @@
+package logger
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Fluent configures a Fluent forward protocol core.
+type Fluent struct {
+	// Network is "tcp" or "unix".
+	// Optional. Default value "tcp".
+	Network string
+
+	// Address is the address of the Fluentd or Fluent Bit forward input.
+	// Optional. Default value "127.0.0.1:24224".
+	Address string
+
+	// Tag is the tag of the events, routing them in Fluentd.
+	Tag string
+
+	// Timeout bounds connecting and writing.
+	// Optional. Default value 5s.
+	Timeout time.Duration
+
+	Batching Batching
+}
+
+// FluentCore is a zapcore.Core shipping entries as Fluent forward protocol
+// events, msgpack-encoded [time, record] arrays sent in batches from a
+// background goroutine. While the connection is down events are buffered
+// and sent once a reconnection succeeds.
+type FluentCore struct {
+	*BatchCore
+	conn *fluentConn
+}
+
+// NewFluentCore returns a core sending to the forward input configured by
+// f. The connection is established on the first send.
+func NewFluentCore(f Fluent, enab zapcore.LevelEnabler) *FluentCore {
+	if f.Network == "" {
+		f.Network = "tcp"
+	}
+	if f.Address == "" {
+		f.Address = "127.0.0.1:24224"
+	}
+	if f.Timeout <= 0 {
+		f.Timeout = 5 * time.Second
+	}
+	conn := &fluentConn{config: f}
+
+	encode := func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		var e msgpackEncoder
+		e.arrayHeader(2)
+		e.eventTime(ent.Time)
+		e.value(entryRecord(ent, nil, fields))
+		return batchEntry{time: ent.Time, data: e.buf}, nil
+	}
+
+	send := func(batch []batchEntry) error {
+		// Forward mode: [tag, [[time, record], ...]].
+		var e msgpackEncoder
+		e.arrayHeader(2)
+		e.string(f.Tag)
+		e.arrayHeader(len(batch))
+		for _, be := range batch {
+			e.buf = append(e.buf, be.data...)
+		}
+		return conn.send(e.buf)
+	}
+
+	c := &FluentCore{BatchCore: newBatchCore(enab, f.Batching, encode, send), conn: conn}
+	closers.remove(c.BatchCore)
+	closers.add(c)
+	return c
+}
+
+// Close sends the buffered events and closes the connection.
+func (c *FluentCore) Close() error {
+	closers.remove(c)
+	err := c.BatchCore.Close()
+	if cerr := c.conn.close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// entryRecord returns the fields of an entry, with its message, level,
+// logger name, caller and stack, as a map.
+func entryRecord(ent zapcore.Entry, with, fields []zapcore.Field) map[string]interface{} {
+	m := zapcore.NewMapObjectEncoder()
+	for _, f := range with {
+		f.AddTo(m)
+	}
+	for _, f := range fields {
+		f.AddTo(m)
+	}
+	m.Fields["message"] = ent.Message
+	m.Fields["level"] = ent.Level.String()
+	if ent.LoggerName != "" {
+		m.Fields["logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		m.Fields["caller"] = ent.Caller.TrimmedPath()
+	}
+	if ent.Stack != "" {
+		m.Fields["stacktrace"] = ent.Stack
+	}
+	return m.Fields
+}
+
+// fluentConn is a reconnecting connection to a forward input.
+type fluentConn struct {
+	config Fluent
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (c *fluentConn) send(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		conn, err := net.DialTimeout(c.config.Network, c.config.Address, c.config.Timeout)
+		if err != nil {
+			return err
+		}
+		c.conn = conn
+	}
+	c.conn.SetWriteDeadline(time.Now().Add(c.config.Timeout))
+	if _, err := c.conn.Write(msg); err != nil {
+		// The batch is retried on a new connection.
+		c.conn.Close()
+		c.conn = nil
+		return err
+	}
+	return nil
+}
+
+func (c *fluentConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// msgpackEncoder encodes the values of zapcore.MapObjectEncoder fields as
+// msgpack.
+type msgpackEncoder struct {
+	buf []byte
+}
+
+func (e *msgpackEncoder) arrayHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xdc)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdd)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) mapHeader(n int) {
+	switch {
+	case n < 16:
+		e.buf = append(e.buf, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xde)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdf)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+}
+
+func (e *msgpackEncoder) string(s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		e.buf = append(e.buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xda)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xdb)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, s...)
+}
+
+func (e *msgpackEncoder) bytes(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, 0xc5)
+		e.buf = binary.BigEndian.AppendUint16(e.buf, uint16(n))
+	default:
+		e.buf = append(e.buf, 0xc6)
+		e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(n))
+	}
+	e.buf = append(e.buf, b...)
+}
+
+func (e *msgpackEncoder) int(i int64) {
+	e.buf = append(e.buf, 0xd3)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, uint64(i))
+}
+
+func (e *msgpackEncoder) uint(u uint64) {
+	e.buf = append(e.buf, 0xcf)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, u)
+}
+
+func (e *msgpackEncoder) float(f float64) {
+	e.buf = append(e.buf, 0xcb)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(f))
+}
+
+// eventTime encodes t as the EventTime extension of the forward protocol.
+func (e *msgpackEncoder) eventTime(t time.Time) {
+	e.buf = append(e.buf, 0xd7, 0x00)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Unix()))
+	e.buf = binary.BigEndian.AppendUint32(e.buf, uint32(t.Nanosecond()))
+}
+
+func (e *msgpackEncoder) value(v interface{}) {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, 0xc0)
+	case bool:
+		if v {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case string:
+		e.string(v)
+	case []byte:
+		e.bytes(v)
+	case int:
+		e.int(int64(v))
+	case int8:
+		e.int(int64(v))
+	case int16:
+		e.int(int64(v))
+	case int32:
+		e.int(int64(v))
+	case int64:
+		e.int(v)
+	case uint:
+		e.uint(uint64(v))
+	case uint8:
+		e.uint(uint64(v))
+	case uint16:
+		e.uint(uint64(v))
+	case uint32:
+		e.uint(uint64(v))
+	case uint64:
+		e.uint(v)
+	case uintptr:
+		e.uint(uint64(v))
+	case float32:
+		e.float(float64(v))
+	case float64:
+		e.float(v)
+	case time.Time:
+		e.string(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		// Seconds, as the production JSON encoder of the other sinks.
+		e.float(v.Seconds())
+	case map[string]interface{}:
+		e.mapHeader(len(v))
+		for k, val := range v {
+			e.string(k)
+			e.value(val)
+		}
+	case []interface{}:
+		e.arrayHeader(len(v))
+		for _, val := range v {
+			e.value(val)
+		}
+	default:
+		e.string(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HeaderCloudTraceContext is the Google Cloud trace propagation header.
+const HeaderCloudTraceContext = "X-Cloud-Trace-Context"
+
+// NewGCPEncoderConfig returns a zapcore.EncoderConfig producing the
+// structured payload Google Cloud Logging expects.
+func NewGCPEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		MessageKey:     "message",
+		LevelKey:       "severity",
+		TimeKey:        "time",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    GCPLevelEncoder,
+		EncodeTime:     zapcore.RFC3339NanoTimeEncoder,
+		EncodeDuration: GCPDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
+// NewGCPConfig returns a logging configuration for Google Cloud Logging.
+func NewGCPConfig(lv zap.AtomicLevel) zap.Config {
+	cfg := zap.NewProductionConfig()
+	cfg.Level = lv
+	cfg.EncoderConfig = NewGCPEncoderConfig()
+	cfg.OutputPaths = []string{"stdout"}
+
+	return cfg
+}
+
+// NewGCPMiddlewareConfig returns a middleware config whose entries render
+// natively in Google Cloud Logging: HTTP fields grouped as httpRequest and
+// traces from X-Cloud-Trace-Context linked to the project's Cloud Trace.
+func NewGCPMiddlewareConfig(projectID string, lv zap.AtomicLevel) Config {
+	logger, err := NewGCPConfig(lv).Build()
+	if err != nil {
+		panic(fmt.Errorf("logging.NewGCPMiddlewareConfig: %v", err))
+	}
+
+	c := DefaultConfig
+	c.Logger = logger
+	c.HTTPRequestKey = "httpRequest"
+	c.FieldNames = FieldNames{
+		RemoteIP:  "remoteIp",
+		Latency:   "latency",
+		Status:    "status",
+		Size:      "responseSize",
+		BytesIn:   "requestSize",
+		UserAgent: "userAgent",
+		Method:    "requestMethod",
+		URI:       "requestUrl",
+		Referer:   "referer",
+		Protocol:  "protocol",
+	}
+	c.DisableHost = true
+	c.LogReferer = true
+	c.LogProtocol = true
+	c.TraceExtractors = []TraceExtractor{CloudTraceContext, OpenTelemetry, W3CTraceContext}
+	c.TraceFormatter = func(tc TraceContext) []zapcore.Field {
+		return []zapcore.Field{
+			zap.String("logging.googleapis.com/trace", "projects/"+projectID+"/traces/"+tc.TraceID),
+			zap.String("logging.googleapis.com/spanId", tc.SpanID),
+			zap.Bool("logging.googleapis.com/trace_sampled", tc.Flags == "01"),
+		}
+	}
+
+	return c
+}
+
+// CloudTraceContext extracts the trace context from the
+// X-Cloud-Trace-Context header, "TRACE_ID/SPAN_ID;o=OPTIONS". The decimal
+// span ID is converted to hex.
+func CloudTraceContext(c echo.Context) (TraceContext, bool) {
+	v := c.Request().Header.Get(HeaderCloudTraceContext)
+	traceID, rest, ok := strings.Cut(v, "/")
+	if !ok || !isHex(strings.ToLower(traceID), 32) {
+		return TraceContext{}, false
+	}
+	spanPart, options, _ := strings.Cut(rest, ";")
+	span, err := strconv.ParseUint(spanPart, 10, 64)
+	if err != nil || span == 0 {
+		return TraceContext{}, false
+	}
+
+	tc := TraceContext{
+		TraceID: strings.ToLower(traceID),
+		SpanID:  fmt.Sprintf("%016x", span),
+		Flags:   "00",
+	}
+	if options == "o=1" {
+		tc.Flags = "01"
+	}
+	return tc, true
+}
+
+// GCPLevelEncoder serializes a Level to a Cloud Logging severity.
+func GCPLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch l {
+	case zapcore.DebugLevel:
+		enc.AppendString("DEBUG")
+	case zapcore.InfoLevel:
+		enc.AppendString("INFO")
+	case zapcore.WarnLevel:
+		enc.AppendString("WARNING")
+	case zapcore.ErrorLevel:
+		enc.AppendString("ERROR")
+	case zapcore.DPanicLevel:
+		enc.AppendString("CRITICAL")
+	case zapcore.PanicLevel:
+		enc.AppendString("ALERT")
+	case zapcore.FatalLevel:
+		enc.AppendString("EMERGENCY")
+	default:
+		enc.AppendString("DEFAULT")
+	}
+}
+
+// GCPDurationEncoder serializes a time.Duration in the "1.5s" format of
+// the Cloud Logging httpRequest latency.
+func GCPDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString(strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s")
+}
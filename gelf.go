@@ -0,0 +1,244 @@
+package logger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// GELF chunking limits.
+const (
+	gelfChunkSize = 8192
+	gelfMaxChunks = 128
+)
+
+var gelfKeyPattern = regexp.MustCompile(`[^\w.\-]`)
+
+// GELF configures a Graylog Extended Log Format core.
+type GELF struct {
+	// Network is "udp" or "tcp".
+	// Optional. Default value "udp".
+	Network string
+
+	// Address is the address of the Graylog GELF input.
+	Address string
+
+	// Host is the reported host.
+	// Optional. Default value os.Hostname.
+	Host string
+
+	// Compress gzips UDP messages.
+	Compress bool
+}
+
+// GELFCore is a zapcore.Core sending entries to Graylog. Fields become
+// GELF additional fields; nested objects are flattened with "_"-joined
+// keys. UDP messages larger than a datagram are chunked, TCP messages are
+// null-byte delimited.
+type GELFCore struct {
+	zapcore.LevelEnabler
+	fields []zapcore.Field
+	conn   *gelfConn
+}
+
+// NewGELFCore connects to the GELF input configured by g.
+func NewGELFCore(g GELF, enab zapcore.LevelEnabler) (*GELFCore, error) {
+	if g.Network == "" {
+		g.Network = "udp"
+	}
+	if g.Host == "" {
+		g.Host, _ = os.Hostname()
+	}
+	conn := &gelfConn{config: g}
+	if err := conn.connect(); err != nil {
+		return nil, err
+	}
+	return &GELFCore{LevelEnabler: enab, conn: conn}, nil
+}
+
+func (c *GELFCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *GELFCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *GELFCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	m := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(m)
+	}
+	for _, f := range fields {
+		f.AddTo(m)
+	}
+
+	msg := map[string]interface{}{
+		"version":       "1.1",
+		"host":          c.conn.config.Host,
+		"short_message": ent.Message,
+		"timestamp":     float64(ent.Time.UnixNano()) / float64(time.Second),
+		"level":         syslogSeverity(ent.Level),
+	}
+	if ent.Stack != "" {
+		msg["full_message"] = ent.Message + "\n" + ent.Stack
+	}
+	if ent.LoggerName != "" {
+		msg["_logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		msg["_caller"] = ent.Caller.TrimmedPath()
+	}
+	for k, v := range m.Fields {
+		addGELFField(msg, "_"+gelfKeyPattern.ReplaceAllString(k, "_"), v)
+	}
+
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return c.conn.write(b)
+}
+
+func (c *GELFCore) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the GELF input.
+func (c *GELFCore) Close() error {
+	return c.conn.close()
+}
+
+// addGELFField adds v to msg under key, flattening objects, since
+// additional fields must be strings or numbers.
+func addGELFField(msg map[string]interface{}, key string, v interface{}) {
+	if key == "_id" {
+		key = "__id"
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, val := range v {
+			addGELFField(msg, key+"_"+gelfKeyPattern.ReplaceAllString(k, "_"), val)
+		}
+	case string, float32, float64, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64:
+		msg[key] = v
+	case []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			msg[key] = fmt.Sprint(v)
+			return
+		}
+		msg[key] = string(b)
+	default:
+		msg[key] = fmt.Sprint(v)
+	}
+}
+
+// gelfConn is a connection to a GELF input, shared by the cores derived
+// with With.
+type gelfConn struct {
+	config GELF
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (c *gelfConn) connect() error {
+	conn, err := net.Dial(c.config.Network, c.config.Address)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+func (c *gelfConn) write(msg []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	if c.config.Network != "udp" {
+		if _, err := c.conn.Write(append(msg, 0)); err != nil {
+			c.conn.Close()
+			c.conn = nil
+			return err
+		}
+		return nil
+	}
+
+	if c.config.Compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		zw.Write(msg)
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		msg = buf.Bytes()
+	}
+	if len(msg) <= gelfChunkSize {
+		_, err := c.conn.Write(msg)
+		return err
+	}
+	return c.writeChunks(msg)
+}
+
+// writeChunks sends msg as GELF chunks sharing a random message ID.
+func (c *gelfConn) writeChunks(msg []byte) error {
+	const headerSize = 12
+	size := gelfChunkSize - headerSize
+	count := (len(msg) + size - 1) / size
+	if count > gelfMaxChunks {
+		return errors.New("logger: GELF message too large")
+	}
+
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, gelfChunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(msg) {
+			end = len(msg)
+		}
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, msg[i*size:end]...)
+		if _, err := c.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *gelfConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"net/netip"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultGeoIPCacheSize is the number of lookups cached by GeoIP when no
+// size is given.
+const DefaultGeoIPCacheSize = 4096
+
+// GeoLocation is the location of an IP address. Empty values are not
+// logged.
+type GeoLocation struct {
+	Country string
+	Region  string
+	City    string
+	ASN     uint
+	ASOrg   string
+}
+
+// GeoIPResolver looks up the location of IP addresses. The maxmind
+// subpackage provides an implementation reading MaxMind databases.
+type GeoIPResolver interface {
+	Lookup(ip netip.Addr) (GeoLocation, error)
+}
+
+// GeoIP returns an enricher, for Config.Enrichers, adding the country,
+// region, city and ASN of the client IP returned by ClientIP. The last
+// cacheSize lookups are cached, so repeated clients add no latency. Failed
+// lookups add no fields, nor does a Config.Privacy hiding the IP.
+func GeoIP(r GeoIPResolver, cacheSize int) func(echo.Context) []zapcore.Field {
+	if cacheSize <= 0 {
+		cacheSize = DefaultGeoIPCacheSize
+	}
+	cache := newLRUCache[netip.Addr, []zapcore.Field](cacheSize)
+
+	return func(c echo.Context) []zapcore.Field {
+		v, ok := ClientIP(c)
+		if !ok {
+			return nil
+		}
+		ip, ok := parseIP(v)
+		if !ok {
+			return nil
+		}
+		if fields, ok := cache.get(ip); ok {
+			return fields
+		}
+		loc, err := r.Lookup(ip)
+		if err != nil {
+			return nil
+		}
+		fields := loc.fields()
+		cache.add(ip, fields)
+		return fields
+	}
+}
+
+func (l GeoLocation) fields() []zapcore.Field {
+	fields := make([]zapcore.Field, 0, 5)
+	if l.Country != "" {
+		fields = append(fields, zap.String("geo_country", l.Country))
+	}
+	if l.Region != "" {
+		fields = append(fields, zap.String("geo_region", l.Region))
+	}
+	if l.City != "" {
+		fields = append(fields, zap.String("geo_city", l.City))
+	}
+	if l.ASN != 0 {
+		fields = append(fields, zap.Uint("geo_asn", l.ASN))
+	}
+	if l.ASOrg != "" {
+		fields = append(fields, zap.String("geo_as_org", l.ASOrg))
+	}
+	return fields
+}
@@ -0,0 +1,25 @@
+package logger
+
+import "go.uber.org/zap"
+
+// ReplaceGlobals installs logger as zap's global logger and redirects the
+// output of the standard library's global logger to it at Info level. It
+// returns a function restoring both.
+func ReplaceGlobals(logger *zap.Logger) func() {
+	undoGlobals := zap.ReplaceGlobals(logger)
+	undoStdLog := zap.RedirectStdLog(logger)
+	return func() {
+		undoStdLog()
+		undoGlobals()
+	}
+}
+
+// WithReplaceGlobals installs the built logger with ReplaceGlobals, so
+// libraries using zap.L() or the standard log package share its sinks. The
+// function restoring the previous globals is stored in undo, if not nil.
+func WithReplaceGlobals(undo *func()) Option {
+	return func(o *options) {
+		o.replaceGlobals = true
+		o.undoGlobals = undo
+	}
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// headerMarshaler logs the allowlisted headers present in h as an object,
+// masking sensitive values.
+type headerMarshaler struct {
+	h        http.Header
+	keys     []string
+	redactor *Redactor
+}
+
+func (m headerMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, k := range m.keys {
+		if v := m.h.Values(k); len(v) > 0 {
+			enc.AddString(k, m.redactor.Redact(k, strings.Join(v, ", ")))
+		}
+	}
+	return nil
+}
+
+// canonicalHeaderKeys returns keys in canonical header key format.
+func canonicalHeaderKeys(keys []string) []string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		out[i] = http.CanonicalHeaderKey(k)
+	}
+	return out
+}
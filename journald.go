@@ -0,0 +1,157 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// JournaldSocket is the socket of the systemd journal native protocol.
+const JournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldCore is a zapcore.Core sending entries to the systemd journal,
+// with their fields as journal fields and their level as PRIORITY, so they
+// can be queried with journalctl, e.g. journalctl STATUS=500.
+type JournaldCore struct {
+	zapcore.LevelEnabler
+	identifier string
+	fields     []zapcore.Field
+	conn       *net.UnixConn
+}
+
+// NewJournaldCore returns a core writing to the local journal. The
+// identifier is the SYSLOG_IDENTIFIER of entries, the program name when
+// empty.
+func NewJournaldCore(identifier string, enab zapcore.LevelEnabler) (*JournaldCore, error) {
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: JournaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldCore{
+		LevelEnabler: enab,
+		identifier:   identifier,
+		conn:         conn,
+	}, nil
+}
+
+func (c *JournaldCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]zapcore.Field(nil), c.fields...), fields...)
+	return &clone
+}
+
+func (c *JournaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *JournaldCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	m := zapcore.NewMapObjectEncoder()
+	for _, f := range c.fields {
+		f.AddTo(m)
+	}
+	for _, f := range fields {
+		f.AddTo(m)
+	}
+
+	var buf bytes.Buffer
+	appendJournalField(&buf, "MESSAGE", ent.Message)
+	appendJournalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(ent.Level)))
+	appendJournalField(&buf, "SYSLOG_IDENTIFIER", c.identifier)
+	if ent.LoggerName != "" {
+		appendJournalField(&buf, "LOGGER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		appendJournalField(&buf, "CODE_FILE", ent.Caller.File)
+		appendJournalField(&buf, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		appendJournalField(&buf, "CODE_FUNC", ent.Caller.Function)
+	}
+	if ent.Stack != "" {
+		appendJournalField(&buf, "STACKTRACE", ent.Stack)
+	}
+
+	keys := make([]string, 0, len(m.Fields))
+	for k := range m.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendJournalField(&buf, journalKey(k), journalValue(m.Fields[k]))
+	}
+
+	_, err := c.conn.Write(buf.Bytes())
+	return err
+}
+
+func (c *JournaldCore) Sync() error {
+	return nil
+}
+
+// Close closes the journal socket.
+func (c *JournaldCore) Close() error {
+	return c.conn.Close()
+}
+
+// appendJournalField appends a field in the journal native protocol
+// format, length-prefixed when the value spans several lines.
+func appendJournalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.ContainsRune(value, '\n') {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalKey returns k as a journal field name: upper case letters,
+// digits and underscores, not starting with an underscore or digit.
+func journalKey(k string) string {
+	b := []byte(strings.ToUpper(k))
+	for i, c := range b {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			b[i] = '_'
+		}
+	}
+	if len(b) == 0 || b[0] == '_' || b[0] >= '0' && b[0] <= '9' {
+		return "F_" + string(b)
+	}
+	return string(b)
+}
+
+// journalValue returns the text of a field value, JSON for composite
+// values.
+func journalValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case fmt.Stringer:
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
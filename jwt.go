@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultJWTContextKey is the context key echo's JWT middleware stores the
+// verified token under.
+const DefaultJWTContextKey = "user"
+
+// JWTClaims returns an enricher, for Config.Enrichers, copying the given
+// claims of the verified token stored under contextKey by echo's JWT
+// middleware into "jwt_<claim>" fields. The token may be a *jwt.Token of
+// any github.com/golang-jwt/jwt version, or its claims. Missing claims are
+// omitted.
+func JWTClaims(contextKey string, claims ...string) func(echo.Context) []zapcore.Field {
+	if contextKey == "" {
+		contextKey = DefaultJWTContextKey
+	}
+	return func(c echo.Context) []zapcore.Field {
+		values := jwtClaims(c.Get(contextKey))
+		if values == nil {
+			return nil
+		}
+		fields := make([]zapcore.Field, 0, len(claims))
+		for _, name := range claims {
+			if v, ok := values[name]; ok {
+				fields = append(fields, zap.Any("jwt_"+name, v))
+			}
+		}
+		return fields
+	}
+}
+
+// jwtClaims returns the claims of a token, or of claims themselves, as a
+// map.
+func jwtClaims(token interface{}) map[string]interface{} {
+	if token == nil {
+		return nil
+	}
+	v := reflect.ValueOf(token)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Struct {
+		if f := v.FieldByName("Claims"); f.IsValid() && f.CanInterface() {
+			return jwtClaims(f.Interface())
+		}
+	}
+
+	if v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String {
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m
+	}
+
+	// Custom claim structs are read through their JSON representation.
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil
+	}
+	return m
+}
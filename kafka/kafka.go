@@ -0,0 +1,49 @@
+// Package kafka provides a logger.Publisher producing to Apache Kafka.
+package kafka
+
+import (
+	"context"
+
+	logger "github.com/glepnir/zapecho"
+	"github.com/segmentio/kafka-go"
+)
+
+// Publisher produces messages with a kafka.Writer. Keyed messages are
+// partitioned by a hash of their key.
+type Publisher struct {
+	w *kafka.Writer
+}
+
+var _ logger.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns a Publisher producing to the given brokers.
+func NewPublisher(brokers ...string) *Publisher {
+	return NewPublisherWithWriter(&kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+	})
+}
+
+// NewPublisherWithWriter returns a Publisher producing with w, which must
+// not have a Topic since the topic is set per message.
+func NewPublisherWithWriter(w *kafka.Writer) *Publisher {
+	return &Publisher{w: w}
+}
+
+// Publish produces msgs.
+func (p *Publisher) Publish(ctx context.Context, msgs []logger.Message) error {
+	kmsgs := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		kmsgs[i] = kafka.Message{Topic: m.Topic, Value: m.Value, Time: m.Time}
+		if m.Key != "" {
+			kmsgs[i].Key = []byte(m.Key)
+		}
+	}
+	return p.w.WriteMessages(ctx, kmsgs...)
+}
+
+// Close flushes pending messages and closes the writer.
+func (p *Publisher) Close() error {
+	return p.w.Close()
+}
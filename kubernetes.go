@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"os"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Environment variables Kubernetes metadata is read from. They must be
+// set from the downward API in the pod spec, e.g.
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom:
+//	    fieldRef:
+//	      fieldPath: metadata.name
+const (
+	EnvPodName      = "POD_NAME"
+	EnvPodNamespace = "POD_NAMESPACE"
+	EnvNodeName     = "NODE_NAME"
+)
+
+// Kubernetes returns an enricher, for Config.Enrichers, adding the pod
+// name, namespace and node, read once from the downward API environment
+// variables, as k8s_pod, k8s_namespace and k8s_node. Unset variables are
+// omitted.
+func Kubernetes() func(echo.Context) []zapcore.Field {
+	fields := KubernetesFields()
+	return func(echo.Context) []zapcore.Field {
+		return fields
+	}
+}
+
+// KubernetesFields returns the Kubernetes metadata fields, to add them to
+// every entry of a logger with zap.Fields.
+func KubernetesFields() []zapcore.Field {
+	fields := make([]zapcore.Field, 0, 3)
+	for _, v := range []struct{ env, key string }{
+		{EnvPodName, "k8s_pod"},
+		{EnvPodNamespace, "k8s_namespace"},
+		{EnvNodeName, "k8s_node"},
+	} {
+		if value := os.Getenv(v.env); value != "" {
+			fields = append(fields, zap.String(v.key, value))
+		}
+	}
+	return fields
+}
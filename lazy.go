@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lazyField computes its field once, the first time it is encoded.
+type lazyField struct {
+	once  sync.Once
+	f     func() zapcore.Field
+	field zapcore.Field
+}
+
+func (l *lazyField) resolve() zapcore.Field {
+	l.once.Do(func() {
+		l.field = l.f()
+		l.f = nil
+	})
+	return l.field
+}
+
+func (l *lazyField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	l.resolve().AddTo(enc)
+	return nil
+}
+
+// Lazy returns a field computed by f only when an entry carrying it is
+// written, so it costs nothing when the entry is dropped by the level or
+// a sampler. f is called at most once, however many cores the entry is
+// written to. Passed to Logger.With, the field is computed immediately;
+// use zap.Logger.WithLazy to defer it there too.
+//
+// The access log middleware resolves lazy fields returned by enrichers
+// before scrubbing them. Elsewhere they are resolved by the encoder,
+// past any core inspecting fields by key.
+func Lazy(f func() zapcore.Field) zapcore.Field {
+	return zap.Inline(&lazyField{f: f})
+}
+
+// resolveLazy replaces the lazy fields of fields by their value.
+func resolveLazy(fields []zapcore.Field) {
+	for i, f := range fields {
+		if f.Type != zapcore.InlineMarshalerType {
+			continue
+		}
+		if l, ok := f.Interface.(*lazyField); ok {
+			fields[i] = l.resolve()
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// DefaultLevelPath is the path the level handler is mounted at by
+// LevelHandler when none is given.
+const DefaultLevelPath = "/log/level"
+
+// LevelHandler mounts the HTTP handler of lv on g, so the level can be
+// read with GET and changed at runtime with PUT, e.g.
+//
+//	admin := e.Group("/admin", basicAuth)
+//	logger.LevelHandler(admin, "", lv)
+//
+//	curl -X PUT localhost:8080/admin/log/level -d '{"level":"debug"}'
+//
+// See zap.AtomicLevel.ServeHTTP for the request and response formats. The
+// group should be protected, as anyone reaching it can change the level.
+func LevelHandler(g *echo.Group, path string, lv zap.AtomicLevel) {
+	if path == "" {
+		path = DefaultLevelPath
+	}
+	h := echo.WrapHandler(lv)
+	g.GET(path, h)
+	g.PUT(path, h)
+}
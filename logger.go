@@ -2,9 +2,7 @@ package logger
 
 import (
 	"fmt"
-	"time"
 
-	"github.com/labstack/echo/v4"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
@@ -22,7 +20,7 @@ func NewDevelopmentEncoderConfig() zapcore.EncoderConfig {
 		LineEnding:     zapcore.DefaultLineEnding,
 		EncodeLevel:    zapcore.CapitalColorLevelEncoder,
 		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: nil,
+		EncodeDuration: zapcore.StringDurationEncoder,
 		EncodeCaller:   zapcore.ShortCallerEncoder,
 	}
 }
@@ -45,14 +43,12 @@ func NewDevelopmentConfig(lv zap.AtomicLevel) zap.Config {
 
 // NewLogger returns the new zap.Logger with concurrency-safe SyncBuffer.
 func NewLogger(lv zap.AtomicLevel, opts ...zap.Option) *zap.Logger {
-	c := zap.NewProductionConfig()
-	c.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-
-	if lv.Level().Enabled(zapcore.DebugLevel) {
-		c = NewDevelopmentConfig(lv)
-	}
+	return NewLoggerWithOptions(lv, WithZapOptions(opts...))
+}
 
-	logger, err := c.Build(opts...)
+// NewLoggerWithOptions returns the new zap.Logger configured by opts.
+func NewLoggerWithOptions(lv zap.AtomicLevel, opts ...Option) *zap.Logger {
+	logger, err := NewLoggerFromConfig(newConfig(lv), opts...)
 	if err != nil {
 		panic(fmt.Errorf("logging.NewLogger: %v", err))
 	}
@@ -60,53 +56,44 @@ func NewLogger(lv zap.AtomicLevel, opts ...zap.Option) *zap.Logger {
 	return logger
 }
 
-func ZapMiddleware(atom zap.AtomicLevel) echo.MiddlewareFunc {
-
-	middlewareLogger := NewLogger(atom)
-
-	defer middlewareLogger.Sync()
-
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			start := time.Now()
-
-			err := next(c)
-			if err != nil {
-				c.Error(err)
-			}
-
-			req := c.Request()
-			res := c.Response()
-
-			fields := []zapcore.Field{
-				zap.String("remote_ip", c.RealIP()),
-				zap.String("latency", time.Since(start).String()),
-				zap.String("host", req.Host),
-				zap.String("request", fmt.Sprintf("%s %s", req.Method, req.RequestURI)),
-				zap.Int("status", res.Status),
-				zap.Int64("size", res.Size),
-				zap.String("user_agent", req.UserAgent()),
-			}
+// newConfig returns the production configuration, or the development one
+// when lv enables debug entries.
+func newConfig(lv zap.AtomicLevel) zap.Config {
+	if lv.Level().Enabled(zapcore.DebugLevel) {
+		return NewDevelopmentConfig(lv)
+	}
+	c := zap.NewProductionConfig()
+	c.Level = lv
+	c.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	return c
+}
 
-			id := req.Header.Get(echo.HeaderXRequestID)
-			if id == "" {
-				id = res.Header().Get(echo.HeaderXRequestID)
-				fields = append(fields, zap.String("request_id", id))
-			}
+// NewLoggerFromConfig returns the new zap.Logger built from c and
+// configured by opts, in place of the production and development
+// configurations of NewLoggerWithOptions. Build errors are returned
+// instead of panicking. Loggers over a custom zapcore.Core are built with
+// TeeBuilder or zap.New.
+func NewLoggerFromConfig(c zap.Config, opts ...Option) (*zap.Logger, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-			n := res.Status
-			switch {
-			case n >= 500:
-				middlewareLogger.Error("Server error", fields...)
-			case n >= 400:
-				middlewareLogger.Warn("Client error", fields...)
-			case n >= 300:
-				middlewareLogger.Info("Redirection", fields...)
-			default:
-				middlewareLogger.Info("Success", fields...)
-			}
+	zapOpts, err := o.apply(&c)
+	if err != nil {
+		return nil, err
+	}
 
-			return nil
+	logger, err := c.Build(zapOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if o.replaceGlobals {
+		undo := ReplaceGlobals(logger)
+		if o.undoGlobals != nil {
+			*o.undoGlobals = undo
 		}
 	}
+
+	return logger, nil
 }
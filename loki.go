@@ -0,0 +1,194 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Loki configures a Grafana Loki push core.
+type Loki struct {
+	// URL is the base URL of Loki, e.g. "http://loki:3100".
+	URL string
+
+	// TenantID is sent as X-Scope-OrgID to multi-tenant Loki.
+	// Optional.
+	TenantID string
+
+	// Username and Password authenticate with basic auth.
+	// Optional.
+	Username string
+	Password string
+
+	// Labels are static stream labels, e.g. {"app": "api"}. A level label
+	// is always added.
+	Labels map[string]string
+
+	// LabelFields are the keys of top-level fields copied into stream
+	// labels, e.g. "route". Keep them low-cardinality.
+	LabelFields []string
+
+	// StatusClassField is the key of a status field added as a
+	// status_class label, e.g. "2xx".
+	// Optional. Default value "", no status class label.
+	StatusClassField string
+
+	// Encoder encodes log lines.
+	// Optional. Default value a JSON encoder.
+	Encoder zapcore.Encoder
+
+	// Client sends the push requests.
+	// Optional. Default value a client with a 10s timeout.
+	Client *http.Client
+
+	Batching Batching
+}
+
+// NewLokiCore returns a core pushing entries to Loki in batches, through
+// the /loki/api/v1/push API.
+func NewLokiCore(l Loki, enab zapcore.LevelEnabler) *BatchCore {
+	if l.Client == nil {
+		l.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	labelFields := make(map[string]string, len(l.LabelFields))
+	for _, k := range l.LabelFields {
+		labelFields[k] = lokiLabelName(k)
+	}
+	encodeLine := encodeJSON(l.Encoder)
+
+	encode := func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		e, err := encodeLine(ent, fields)
+		if err != nil {
+			return e, err
+		}
+		labels := make(map[string]string, len(l.Labels)+len(labelFields)+2)
+		for k, v := range l.Labels {
+			labels[k] = v
+		}
+		labels["level"] = ent.Level.String()
+		for _, f := range fields {
+			if name, ok := labelFields[f.Key]; ok {
+				labels[name] = fieldString(f)
+			}
+			if f.Key == l.StatusClassField && l.StatusClassField != "" {
+				if status, err := strconv.Atoi(fieldString(f)); err == nil {
+					labels["status_class"] = strconv.Itoa(status/100) + "xx"
+				}
+			}
+		}
+		key, err := json.Marshal(labels)
+		if err != nil {
+			return e, err
+		}
+		e.key, e.value = string(key), labels
+		return e, nil
+	}
+
+	url := strings.TrimSuffix(l.URL, "/") + "/loki/api/v1/push"
+	send := func(batch []batchEntry) error {
+		body, err := lokiPush(batch)
+		if err != nil {
+			// Sending the batch again cannot fix its encoding.
+			return permanentError{err}
+		}
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if l.TenantID != "" {
+			req.Header.Set("X-Scope-OrgID", l.TenantID)
+		}
+		if l.Username != "" {
+			req.SetBasicAuth(l.Username, l.Password)
+		}
+		return doHTTP(l.Client, req)
+	}
+
+	return newBatchCore(enab, l.Batching, encode, send)
+}
+
+type lokiStreamEntry struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiPush returns the push request body of a batch, with the entries
+// grouped by stream.
+func lokiPush(batch []batchEntry) ([]byte, error) {
+	var streams []*lokiStreamEntry
+	byKey := make(map[string]*lokiStreamEntry)
+	for _, e := range batch {
+		s, ok := byKey[e.key]
+		if !ok {
+			s = &lokiStreamEntry{Stream: e.value.(map[string]string)}
+			byKey[e.key] = s
+			streams = append(streams, s)
+		}
+		s.Values = append(s.Values, [2]string{strconv.FormatInt(e.time.UnixNano(), 10), string(e.data)})
+	}
+	return json.Marshal(map[string]interface{}{"streams": streams})
+}
+
+// lokiLabelName returns k as a Prometheus label name.
+func lokiLabelName(k string) string {
+	b := []byte(k)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || i > 0 && c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// fieldString returns the value of a scalar field as text.
+func fieldString(f zapcore.Field) string {
+	switch f.Type {
+	case zapcore.StringType:
+		return f.String
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return strconv.FormatInt(f.Integer, 10)
+	case zapcore.Uint64Type, zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return strconv.FormatUint(uint64(f.Integer), 10)
+	case zapcore.BoolType:
+		return strconv.FormatBool(f.Integer == 1)
+	}
+	m := zapcore.NewMapObjectEncoder()
+	f.AddTo(m)
+	return fmt.Sprint(m.Fields[f.Key])
+}
+
+// doHTTP sends req and returns an error for non-2xx responses.
+func doHTTP(client *http.Client, req *http.Request) error {
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode/100 != 2 {
+		return statusError(req, res)
+	}
+	io.Copy(io.Discard, res.Body)
+	return nil
+}
+
+// statusError returns the error of a non-2xx response, permanent for the
+// 4xx statuses other than 408 and 429.
+func statusError(req *http.Request, res *http.Response) error {
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+	err := fmt.Errorf("logger: %s %s: %s: %s", req.Method, req.URL.Redacted(), res.Status, bytes.TrimSpace(b))
+	if res.StatusCode/100 == 4 && res.StatusCode != http.StatusTooManyRequests &&
+		res.StatusCode != http.StatusRequestTimeout {
+		// The batch is rejected, sending it again would fail again.
+		return permanentError{err}
+	}
+	return err
+}
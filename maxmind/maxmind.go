@@ -0,0 +1,79 @@
+// Package maxmind provides a logger.GeoIPResolver reading MaxMind GeoIP2
+// and GeoLite2 databases.
+package maxmind
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+
+	logger "github.com/glepnir/zapecho"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// Resolver looks up locations in a City database and autonomous systems
+// in an ASN database.
+type Resolver struct {
+	city *geoip2.Reader
+	asn  *geoip2.Reader
+}
+
+var _ logger.GeoIPResolver = (*Resolver)(nil)
+
+// Open opens the City and ASN databases at the given paths. Either may be
+// empty to skip the lookups it provides.
+func Open(cityDB, asnDB string) (*Resolver, error) {
+	r := &Resolver{}
+	var err error
+	if cityDB != "" {
+		if r.city, err = geoip2.Open(cityDB); err != nil {
+			return nil, err
+		}
+	}
+	if asnDB != "" {
+		if r.asn, err = geoip2.Open(asnDB); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Lookup returns the location of ip.
+func (r *Resolver) Lookup(ip netip.Addr) (logger.GeoLocation, error) {
+	var loc logger.GeoLocation
+	addr := net.IP(ip.AsSlice())
+
+	if r.city != nil {
+		city, err := r.city.City(addr)
+		if err != nil {
+			return loc, err
+		}
+		loc.Country = city.Country.IsoCode
+		if len(city.Subdivisions) > 0 {
+			loc.Region = city.Subdivisions[0].IsoCode
+		}
+		loc.City = city.City.Names["en"]
+	}
+	if r.asn != nil {
+		asn, err := r.asn.ASN(addr)
+		if err != nil {
+			return loc, err
+		}
+		loc.ASN = asn.AutonomousSystemNumber
+		loc.ASOrg = asn.AutonomousSystemOrganization
+	}
+	return loc, nil
+}
+
+// Close closes the databases.
+func (r *Resolver) Close() error {
+	var errs []error
+	if r.city != nil {
+		errs = append(errs, r.city.Close())
+	}
+	if r.asn != nil {
+		errs = append(errs, r.asn.Close())
+	}
+	return errors.Join(errs...)
+}
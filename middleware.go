@@ -0,0 +1,922 @@
+package logger
+
+import (
+	"errors"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config defines the config for the access log middleware.
+type Config struct {
+	// Skipper defines a function to skip middleware.
+	Skipper middleware.Skipper
+
+	// Probes detects health and readiness probes, whose successful
+	// requests are logged at Debug or dropped.
+	// Optional. Default value nil, no detection.
+	Probes *Probes
+
+	// QuietSources demotes or drops successful requests from the given
+	// client networks.
+	// Optional. Default value nil.
+	QuietSources *Sources
+
+	// SkipPreflight skips CORS preflight requests, OPTIONS requests with an
+	// Access-Control-Request-Method header. QuietPreflight logs successful
+	// ones at Debug instead.
+	SkipPreflight  bool
+	QuietPreflight bool
+
+	// StaticAssets detects static asset requests, whose successful requests
+	// are logged at Debug, dropped or aggregated.
+	// Optional. Default value nil, no detection.
+	StaticAssets *StaticAssets
+
+	// SkipPaths are request paths that are not logged, either exact paths
+	// such as "/healthz", prefixes such as "/static/*" matching everything
+	// below, or path.Match globs such as "/*.ico".
+	// Optional. Default value nil.
+	SkipPaths []string
+
+	// Logger is the logger access entries are written to.
+	// Optional. Default value is a logger built by NewLogger from Level.
+	Logger *zap.Logger
+
+	// Level is the level used to build the default logger. It is ignored
+	// when Logger is set.
+	// Optional. Default value is zap.InfoLevel.
+	Level zap.AtomicLevel
+
+	// HandleError makes the middleware call c.Error with the handler error
+	// before logging, so the logged status is the one written by the error
+	// handler. When false the error is only logged and passed through, and
+	// the status is derived from the error. Leave it false when another
+	// middleware or the server's HTTPErrorHandler already handles errors.
+	// ZapMiddleware and ZapMiddlewareFromLogger enable it.
+	HandleError bool
+
+	// ContextLogger stores a child of Logger, pre-populated with the request
+	// metadata, in the echo.Context so handlers can retrieve it with
+	// FromContext.
+	ContextLogger bool
+
+	// Enrichers return extra fields appended to every access log entry.
+	// They are skipped for entries dropped by the level or a sampler,
+	// unless SpanEvents or OnServerError still need them.
+	Enrichers []func(echo.Context) []zapcore.Field
+
+	// FieldNames renames the access log fields.
+	// Optional. Default value DefaultFieldNames.
+	FieldNames FieldNames
+
+	// LatencyAsString logs latency as text such as "1.2034ms" instead of a
+	// zap.Duration, which the encoder renders according to its
+	// EncodeDuration setting.
+	LatencyAsString bool
+
+	// LatencyUnit adds a float64 latency field expressed in the unit, one of
+	// time.Nanosecond, time.Microsecond, time.Millisecond or time.Second. The
+	// field is named FieldNames.ScaledLatency, "latency_ms" for milliseconds
+	// by default. Set DisableLatency to log it instead of the regular field.
+	// Optional. Default value 0, no scaled latency field.
+	LatencyUnit time.Duration
+
+	// GenerateRequestID generates a request ID with RequestIDGenerator
+	// when neither the request nor the response carries X-Request-ID, and
+	// sets it on the response header.
+	GenerateRequestID bool
+
+	// RequestIDGenerator generates request IDs for GenerateRequestID.
+	// Optional. Default value NewUUID.
+	RequestIDGenerator func() string
+
+	// TraceExtractors extract the trace context of a request, added as
+	// trace_id, span_id and trace_flags fields. The first extractor that
+	// finds one wins.
+	// Optional. Default value nil, DefaultConfig uses OpenTelemetry and
+	// W3CTraceContext.
+	TraceExtractors []TraceExtractor
+
+	// TraceFormatter turns the extracted trace context into fields.
+	// Optional. Default value logs the FieldNames trace fields.
+	TraceFormatter func(TraceContext) []zapcore.Field
+
+	// SpanEvents also records every access log entry as an event on the
+	// OpenTelemetry span in the request context, if it is recording.
+	SpanEvents bool
+
+	// HTTPRequestKey groups the HTTP fields, remote IP through user agent,
+	// into an object logged under this key instead of at the top level.
+	// Optional. Default value "", no grouping.
+	HTTPRequestKey string
+
+	// LogRequestBody logs up to RequestBodyLimit bytes of request bodies
+	// whose content type is one of RequestBodyContentTypes. The captured
+	// bytes are replayed to the handler, which reads the body unchanged.
+	// The bytes_in field then counts the body bytes actually read instead
+	// of reporting the Content-Length.
+	LogRequestBody bool
+
+	// RequestBodyLimit is the maximum number of body bytes logged.
+	// Optional. Default value DefaultBodyLimit.
+	RequestBodyLimit int64
+
+	// RequestBodyContentTypes are the media types whose bodies are logged,
+	// "type/*" matches any subtype.
+	// Optional. Default value DefaultBodyContentTypes.
+	RequestBodyContentTypes []string
+
+	// LogResponseBody logs up to ResponseBodyLimit bytes of the response
+	// body when the status is at least ResponseBodyMinStatus.
+	LogResponseBody bool
+
+	// ResponseBodyLimit is the maximum number of response bytes logged.
+	// Optional. Default value DefaultBodyLimit.
+	ResponseBodyLimit int
+
+	// ResponseBodyMinStatus is the lowest status whose body is logged.
+	// Optional. Default value 400.
+	ResponseBodyMinStatus int
+
+	// RequestHeaders and ResponseHeaders are the allowlists of headers
+	// logged as request_headers and response_headers objects. Headers not
+	// listed are never logged.
+	// Optional. Default value nil, no headers.
+	RequestHeaders  []string
+	ResponseHeaders []string
+
+	// Redactor masks sensitive header values.
+	// Optional. Default value DefaultRedactor, extend it with
+	// DefaultRedactor.With.
+	Redactor *Redactor
+
+	// LogQuery logs the query parameters as a query object.
+	LogQuery bool
+
+	// LogProtocol logs the protocol version, e.g. "HTTP/2.0".
+	LogProtocol bool
+
+	// LogForwardedFor logs the full X-Forwarded-For chain and the address
+	// of the immediate peer next to the remote IP, to reveal spoofing and
+	// proxy misconfiguration. Both are omitted in privacy mode.
+	LogForwardedFor bool
+
+	// LogTLS logs the TLS version, cipher suite and SNI server name of
+	// requests received over TLS.
+	LogTLS bool
+
+	// LogClientCert logs the subject common name, issuer, serial number and
+	// SHA-256 fingerprint of verified mutual TLS client certificates.
+	LogClientCert bool
+
+	// LogReferer logs the Referer header.
+	LogReferer bool
+
+	// LogBot logs whether the user agent matches one of BotPatterns as
+	// is_bot, to separate automated from human traffic.
+	LogBot bool
+
+	// BotPatterns are case-insensitive substrings of the user agents of
+	// bots, crawlers and monitoring agents.
+	// Optional. Default value DefaultBotPatterns.
+	BotPatterns []string
+
+	// LogContentType logs the request and response Content-Type headers.
+	LogContentType bool
+
+	// QueryRedactor masks sensitive query parameter values, both in the
+	// query object and in the query string of the request and uri fields.
+	// Optional. Default value DefaultQueryRedactor.
+	QueryRedactor *Redactor
+
+	// Scrubbers mask personal data in the string fields named by
+	// ScrubFields, or in every top-level string field when ScrubFields is
+	// empty, before the entry is written. See also WithScrubbers.
+	// Optional. Default value nil, no scrubbing.
+	Scrubbers   []Scrubber
+	ScrubFields []string
+
+	// TrustedProxies are the networks of the proxies whose client IP
+	// headers are trusted. When set, the remote IP is resolved from the
+	// IPHeaders set by trusted proxies only, instead of by
+	// echo.Context.RealIP, so clients cannot spoof it.
+	// Optional. Default value nil, echo.Context.RealIP is used.
+	TrustedProxies []netip.Prefix
+
+	// IPHeaders is the precedence of the headers the client IP is read
+	// from when TrustedProxies is set.
+	// Optional. Default value DefaultIPHeaders.
+	IPHeaders []string
+
+	// NormalizeIP logs the remote IP in canonical form, without brackets
+	// or port, so it can be aggregated.
+	NormalizeIP bool
+
+	// IPv6PrefixBits collapses normalized IPv6 remote IPs to their network
+	// of the given size, e.g. 64 logs "2001:db8:1:2::/64".
+	// Optional. Default value 0, full addresses.
+	IPv6PrefixBits int
+
+	// Privacy enables the privacy mode, see Privacy. DefaultPrivacy is a
+	// ready to use setting.
+	// Optional. Default value nil, privacy mode disabled.
+	Privacy *Privacy
+
+	// LevelFunc returns the level a request is logged at from its status
+	// and handler error.
+	// Optional. Default value DefaultLevelFunc.
+	LevelFunc func(status int, err error) zapcore.Level
+
+	// SlowThreshold marks requests taking longer with a slow field and logs
+	// them at least at Warn, even when ErrorsOnly or SuccessSampling would
+	// discard them.
+	// Optional. Default value 0, disabled.
+	SlowThreshold time.Duration
+
+	// DebugHeader is a request header, such as HeaderDebugLog, enabling
+	// verbose logging for a single request: its ContextLogger logs at
+	// Debug, and the request start, the optional request fields and the
+	// bodies are logged. The header value must equal DebugSecret, or be
+	// "1" or "true" when DebugSecret is empty, which lets any client log
+	// its bodies, so set DebugSecret in production.
+	// Optional. Default value "", disabled.
+	DebugHeader string
+	DebugSecret string
+
+	// SyncInterval syncs the logger periodically, flushing buffered
+	// outputs even when traffic stops.
+	// Optional. Default value 0, no periodic sync.
+	SyncInterval time.Duration
+
+	// SyncOnError syncs the logger after every Error entry, so errors are
+	// persisted before a crash can lose them.
+	SyncOnError bool
+
+	// LogRequestStart also logs a Messages.Started entry at Debug when a
+	// request arrives, which shows requests that never complete.
+	LogRequestStart bool
+
+	// Recover recovers from panics in the handler chain. The panic is
+	// returned as an error, answered with a 500, and its value and stack
+	// are added to the access log entry.
+	Recover bool
+
+	// StacktraceOnServerError adds a stacktrace field to entries whose
+	// handler error records its stack, such as the errors of
+	// github.com/pkg/errors, or whose status is 5xx, in which case the
+	// stack of the middleware is captured.
+	StacktraceOnServerError bool
+
+	// DPanicOnServerError logs 5xx responses at DPanic instead of Error, so
+	// they panic after being logged by a development logger and are plain
+	// errors in production.
+	DPanicOnServerError bool
+
+	// Messages are the access log messages per status class.
+	// Optional. Default value DefaultMessages.
+	Messages Messages
+
+	// MessageFunc returns the access log message of a request, falling
+	// back to Messages when it returns "".
+	// Optional. Default value nil.
+	MessageFunc func(c echo.Context) string
+
+	// ErrorsOnly logs only requests with a 4xx or 5xx status.
+	ErrorsOnly bool
+
+	// SuccessSampling logs only one in every SuccessSampling requests with a
+	// 1xx, 2xx or 3xx status. Failed requests are always logged.
+	// Optional. Default value 0, every request is logged.
+	SuccessSampling uint64
+
+	// OnDiscard is called for every request the middleware does not log,
+	// e.g. to count them.
+	// Optional. Default value nil.
+	OnDiscard func(c echo.Context, status int)
+
+	// OnServerError is called, after the access log entry is written, for
+	// every logged request with a 5xx status, including recovered panics,
+	// e.g. to report them to an error tracker. The fields of the
+	// ServerError are reused once it returns and must not be retained.
+	// Optional. Default value nil.
+	OnServerError func(c echo.Context, e ServerError)
+
+	// Field toggles. Every field is logged unless explicitly disabled.
+	DisableRemoteIP  bool
+	DisableLatency   bool
+	DisableHost      bool
+	DisableMethod    bool
+	DisableURI       bool
+	DisableStatus    bool
+	DisableSize      bool
+	DisableUserAgent bool
+	DisableRequestID bool
+	DisableRoute     bool
+	DisableAuthUser  bool
+	DisableBytesIn   bool
+	DisableError     bool
+	DisableHTTPError bool
+
+	// Optional fields, off by default.
+	LogPath bool
+
+	// AbsoluteURI logs the URI as an absolute URL, with the scheme and
+	// host of the request unless the request line already has them.
+	AbsoluteURI bool
+
+	// LogRequest also logs the method and URI combined in a request field,
+	// e.g. "GET /users?page=2", as earlier versions did by default.
+	LogRequest bool
+}
+
+// DefaultConfig is the default access log middleware config.
+var DefaultConfig = Config{
+	Skipper:     middleware.DefaultSkipper,
+	HandleError: true,
+	TraceExtractors: []TraceExtractor{
+		OpenTelemetry,
+		W3CTraceContext,
+	},
+}
+
+// ZapMiddleware returns a middleware that logs every request with a logger
+// built by NewLogger at the given level.
+func ZapMiddleware(atom zap.AtomicLevel) echo.MiddlewareFunc {
+	c := DefaultConfig
+	c.Level = atom
+	return ZapMiddlewareWithConfig(c)
+}
+
+// ZapMiddlewareFromLogger returns a middleware that logs every request with
+// the given logger instead of building one with NewLogger.
+func ZapMiddlewareFromLogger(logger *zap.Logger) echo.MiddlewareFunc {
+	c := DefaultConfig
+	c.Logger = logger
+	return ZapMiddlewareWithConfig(c)
+}
+
+// ZapMiddlewareWithConfig returns an access log middleware with config.
+// Errors returned by the next handler are logged and then returned up the
+// middleware chain.
+// See: `ZapMiddleware()`.
+func ZapMiddlewareWithConfig(config Config) echo.MiddlewareFunc {
+	mw, _ := ZapMiddlewareWithClose(config)
+	return mw
+}
+
+// ZapMiddlewareWithClose is ZapMiddlewareWithConfig also returning a
+// function stopping the background sync of the middleware's logger and
+// its static asset summaries, then syncing it, to be called on shutdown.
+// Shutdown calls it too.
+func ZapMiddlewareWithClose(config Config) (echo.MiddlewareFunc, func() error) {
+	if config.DebugHeader != "" {
+		return debugMiddleware(config)
+	}
+
+	// Defaults
+	if config.Skipper == nil {
+		config.Skipper = DefaultConfig.Skipper
+	}
+	if config.LevelFunc == nil {
+		config.LevelFunc = DefaultLevelFunc
+	}
+	if config.RequestIDGenerator == nil {
+		config.RequestIDGenerator = NewUUID
+	}
+	if config.RequestBodyLimit <= 0 {
+		config.RequestBodyLimit = DefaultBodyLimit
+	}
+	if len(config.RequestBodyContentTypes) == 0 {
+		config.RequestBodyContentTypes = DefaultBodyContentTypes
+	}
+	if config.ResponseBodyLimit <= 0 {
+		config.ResponseBodyLimit = DefaultBodyLimit
+	}
+	if config.ResponseBodyMinStatus == 0 {
+		config.ResponseBodyMinStatus = http.StatusBadRequest
+	}
+	if config.Redactor == nil {
+		config.Redactor = DefaultRedactor
+	}
+	if config.QueryRedactor == nil {
+		config.QueryRedactor = DefaultQueryRedactor
+	}
+	config.RequestHeaders = canonicalHeaderKeys(config.RequestHeaders)
+	config.ResponseHeaders = canonicalHeaderKeys(config.ResponseHeaders)
+	config.Messages = config.Messages.withDefaults()
+	config.FieldNames = config.FieldNames.withDefaults()
+	if config.TraceFormatter == nil {
+		config.TraceFormatter = config.FieldNames.traceFields
+	}
+	if config.Logger == nil {
+		config.Logger = defaultLogger(config)
+	}
+
+	if config.LatencyUnit != 0 && config.FieldNames.ScaledLatency == "" {
+		config.FieldNames.ScaledLatency = config.FieldNames.Latency + "_" + unitSuffix(config.LatencyUnit)
+	}
+
+	middlewareLogger := config.Logger
+	scrubber := newFieldScrubber(config.Scrubbers, config.ScrubFields)
+	skipPaths := newPathMatcher(config.SkipPaths)
+	probes := newProbeMatcher(config.Probes)
+	assets := newStaticMatcher(config.StaticAssets, middlewareLogger)
+	ips := newIPResolver(config.TrustedProxies, config.IPHeaders)
+	var bots *botMatcher
+	if config.LogBot {
+		bots = newBotMatcher(config.BotPatterns)
+	}
+	names := config.FieldNames
+
+	var successes atomic.Uint64
+	sampleSuccess := func() bool {
+		if config.SuccessSampling <= 1 {
+			return true
+		}
+		return (successes.Add(1)-1)%config.SuccessSampling == 0
+	}
+
+	syncer := newLogSyncer(middlewareLogger, config.SyncInterval)
+	closeFunc := func() error {
+		assets.Close()
+		return syncer.Close()
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.Skipper(c) || skipPaths.match(c.Request().URL.Path) || config.SkipPreflight && isPreflight(c) {
+				return next(c)
+			}
+
+			start := time.Now()
+
+			id := requestID(c)
+			if id == "" && config.GenerateRequestID {
+				id = config.RequestIDGenerator()
+				c.Response().Header().Set(echo.HeaderXRequestID, id)
+			}
+
+			traceFields := extractTrace(c, config.TraceExtractors, config.TraceFormatter)
+
+			realIP := ips.clientIP(c)
+			remoteIP, logRemoteIP := realIP, !config.DisableRemoteIP
+			if config.NormalizeIP {
+				remoteIP = normalizeIP(remoteIP, config.IPv6PrefixBits)
+			}
+			if config.Privacy != nil {
+				var ok bool
+				remoteIP, ok = config.Privacy.anonymize(remoteIP)
+				logRemoteIP = logRemoteIP && ok
+			} else if len(config.Enrichers) > 0 {
+				c.Set(clientIPKey, realIP)
+			}
+
+			if config.ContextLogger {
+				ctxIP, ctxID := "", id
+				if logRemoteIP {
+					ctxIP = remoteIP
+				}
+				if config.Privacy != nil {
+					ctxID = config.Privacy.requestID(id)
+				}
+				c.Set(ContextKey, contextLogger(middlewareLogger, c, names, ctxIP, ctxID).With(traceFields...))
+			}
+
+			if config.LogRequestStart {
+				if ce := middlewareLogger.Check(zapcore.DebugLevel, config.Messages.Started); ce != nil {
+					req := c.Request()
+					uri, startID := redactURI(req.RequestURI, config.QueryRedactor), id
+					if config.Privacy != nil {
+						uri, startID = stripQuery(uri), config.Privacy.requestID(id)
+					}
+					ce.Write(
+						zap.String(names.Method, req.Method),
+						zap.String(names.URI, uri),
+						zap.String(names.RequestID, startID),
+					)
+				}
+			}
+
+			var (
+				reqBody  []byte
+				captured bool
+				counter  *countingReader
+			)
+			if config.LogRequestBody {
+				counter = countRequestBody(c)
+				reqBody, captured = captureRequestBody(c, config.RequestBodyLimit, config.RequestBodyContentTypes)
+			}
+
+			var recorder *bodyRecorder
+			if config.LogResponseBody {
+				res := c.Response()
+				recorder = &bodyRecorder{ResponseWriter: res.Writer, limit: config.ResponseBodyLimit, minStatus: config.ResponseBodyMinStatus}
+				res.Writer = recorder
+				defer func() { res.Writer = recorder.ResponseWriter }()
+			}
+
+			var (
+				err         error
+				panicFields []zapcore.Field
+			)
+			if config.Recover {
+				panicFields, err = recoverNext(next, c, names)
+			} else {
+				err = next(c)
+			}
+			if err != nil && config.HandleError {
+				c.Error(err)
+			}
+
+			latency := time.Since(start)
+			slow := config.SlowThreshold > 0 && latency > config.SlowThreshold
+
+			n := responseStatus(c, err, config.HandleError)
+			var quiet, drop bool
+			if n < http.StatusBadRequest {
+				if probes.match(c) {
+					quiet, drop = true, probes.drop
+				}
+				if config.QuietPreflight && isPreflight(c) {
+					quiet = true
+				}
+				if config.QuietSources.match(realIP) {
+					quiet, drop = true, drop || config.QuietSources.Drop
+				}
+				if !slow && assets.match(c) {
+					quiet, drop = true, drop || assets.drop
+					if assets.interval > 0 && !drop {
+						assets.aggregate(c.Response().Size)
+						return err
+					}
+				}
+			}
+			if n < http.StatusBadRequest && !slow && (config.ErrorsOnly || drop || !sampleSuccess()) {
+				if config.OnDiscard != nil {
+					config.OnDiscard(c, n)
+				}
+				return err
+			}
+
+			lvl := config.LevelFunc(n, err)
+			if quiet {
+				lvl = zapcore.DebugLevel
+			}
+			if slow && lvl < zapcore.WarnLevel {
+				lvl = zapcore.WarnLevel
+			}
+			if config.DPanicOnServerError && n >= http.StatusInternalServerError && lvl == zapcore.ErrorLevel {
+				lvl = zapcore.DPanicLevel
+			}
+
+			var msg string
+			if config.MessageFunc != nil {
+				msg = config.MessageFunc(c)
+			}
+			if msg == "" {
+				msg = config.Messages.message(n)
+			}
+
+			// Fields are only built for entries that are written or hooked.
+			ce := middlewareLogger.Check(lvl, msg)
+			if ce == nil && !config.SpanEvents && (config.OnServerError == nil || n < http.StatusInternalServerError) {
+				return err
+			}
+
+			req := c.Request()
+			res := c.Response()
+
+			buf := getFields()
+			fields := buf.http
+			if logRemoteIP {
+				fields = append(fields, zap.String(names.RemoteIP, remoteIP))
+			}
+			if !config.DisableLatency {
+				if config.LatencyAsString {
+					fields = append(fields, zap.String(names.Latency, latency.String()))
+				} else {
+					fields = append(fields, zap.Duration(names.Latency, latency))
+				}
+			}
+			if config.LatencyUnit != 0 {
+				fields = append(fields, zap.Float64(names.ScaledLatency, float64(latency)/float64(config.LatencyUnit)))
+			}
+			if !config.DisableHost {
+				fields = append(fields, hostFields(c, names)...)
+			}
+			uri := redactURI(req.RequestURI, config.QueryRedactor)
+			if config.Privacy != nil {
+				uri = stripQuery(uri)
+			}
+			if !config.DisableMethod {
+				fields = append(fields, zap.String(names.Method, req.Method))
+			}
+			if !config.DisableURI {
+				if config.AbsoluteURI && strings.HasPrefix(uri, "/") {
+					fields = append(fields, zap.String(names.URI, c.Scheme()+"://"+req.Host+uri))
+				} else {
+					fields = append(fields, zap.String(names.URI, uri))
+				}
+			}
+			if config.LogRequest {
+				fields = append(fields, zap.String(names.Request, req.Method+" "+uri))
+			}
+			if config.LogPath {
+				fields = append(fields, zap.String(names.Path, req.URL.Path))
+			}
+			if config.LogQuery && config.Privacy == nil {
+				fields = append(fields, zap.Object(names.Query, queryMarshaler{c.QueryParams(), config.QueryRedactor}))
+			}
+			if !config.DisableStatus {
+				fields = append(fields, zap.Int(names.Status, n))
+			}
+			if !config.DisableSize {
+				fields = append(fields, zap.Int64(names.Size, res.Size))
+			}
+			if !config.DisableBytesIn {
+				switch {
+				case counter != nil:
+					fields = append(fields, zap.Int64(names.BytesIn, counter.n))
+				case req.ContentLength >= 0:
+					fields = append(fields, zap.Int64(names.BytesIn, req.ContentLength))
+				}
+			}
+			if !config.DisableUserAgent {
+				ua, ok := req.UserAgent(), true
+				if config.Privacy != nil {
+					ua, ok = config.Privacy.anonymize(ua)
+				}
+				if ok {
+					fields = append(fields, zap.String(names.UserAgent, ua))
+				}
+			}
+			if config.LogProtocol {
+				fields = append(fields, zap.String(names.Protocol, req.Proto))
+			}
+			if config.LogReferer {
+				fields = append(fields, zap.String(names.Referer, req.Referer()))
+			}
+			if config.LogContentType {
+				fields = append(fields,
+					zap.String(names.RequestContentType, req.Header.Get(echo.HeaderContentType)),
+					zap.String(names.ResponseContentType, res.Header().Get(echo.HeaderContentType)),
+				)
+			}
+			if !config.DisableAuthUser {
+				if user, _, ok := req.BasicAuth(); ok && user != "" {
+					if config.Privacy != nil {
+						user, ok = config.Privacy.anonymize(user)
+					}
+					if ok {
+						fields = append(fields, zap.String(names.AuthUser, user))
+					}
+				}
+			}
+			if config.HTTPRequestKey != "" {
+				buf.http = fields
+				fields = append(buf.all, zap.Object(config.HTTPRequestKey, fieldsMarshaler(buf.http)))
+			}
+
+			if config.LogForwardedFor && config.Privacy == nil {
+				fields = append(fields, zap.String(names.ForwardedFor, strings.Join(req.Header.Values(echo.HeaderXForwardedFor), ", ")))
+				fields = append(fields, peerFields(req.RemoteAddr, names)...)
+			}
+			if config.LogTLS {
+				fields = append(fields, tlsFields(req.TLS, names)...)
+			}
+			if config.LogClientCert {
+				fields = append(fields, clientCertFields(req.TLS, names)...)
+			}
+			if slow {
+				fields = append(fields, zap.Bool(names.Slow, true))
+			}
+			if config.LogBot {
+				fields = append(fields, zap.Bool(names.Bot, bots.match(req.UserAgent())))
+			}
+			if !config.DisableRoute {
+				fields = append(fields, zap.String(names.Route, c.Path()))
+			}
+			if !config.DisableRequestID {
+				if id == "" {
+					id = res.Header().Get(echo.HeaderXRequestID)
+				}
+				if config.Privacy != nil {
+					id = config.Privacy.requestID(id)
+				}
+				fields = append(fields, zap.String(names.RequestID, id))
+			}
+
+			if len(config.RequestHeaders) > 0 {
+				fields = append(fields, zap.Object(names.RequestHeaders, headerMarshaler{req.Header, config.RequestHeaders, config.Redactor}))
+			}
+			if len(config.ResponseHeaders) > 0 {
+				fields = append(fields, zap.Object(names.ResponseHeaders, headerMarshaler{res.Header(), config.ResponseHeaders, config.Redactor}))
+			}
+			if captured {
+				fields = append(fields, zap.ByteString(names.RequestBody, reqBody))
+			}
+			if recorder != nil && n >= config.ResponseBodyMinStatus {
+				fields = append(fields, zap.ByteString(names.ResponseBody, recorder.buf.Bytes()))
+			}
+
+			if !config.DisableError {
+				fields = append(fields, errorFields(err, names)...)
+			}
+			if !config.DisableHTTPError {
+				fields = append(fields, httpErrorFields(err, names)...)
+			}
+			fields = append(fields, panicFields...)
+			if config.StacktraceOnServerError && panicFields == nil {
+				if stack, ok := errorStack(err); ok {
+					fields = append(fields, zap.String(names.Stacktrace, stack))
+				} else if n >= http.StatusInternalServerError {
+					fields = append(fields, zap.StackSkip(names.Stacktrace, 1))
+				}
+			}
+			fields = append(fields, traceFields...)
+
+			for _, enrich := range config.Enrichers {
+				fields = append(fields, enrich(c)...)
+			}
+
+			resolveLazy(fields)
+			scrubber.scrub(fields)
+
+			if ce != nil {
+				ce.Write(fields...)
+				if config.SyncOnError && lvl >= zapcore.ErrorLevel {
+					middlewareLogger.Sync()
+				}
+			}
+			if config.SpanEvents {
+				addSpanEvent(c, msg, fields)
+			}
+			if config.OnServerError != nil && n >= http.StatusInternalServerError {
+				config.OnServerError(c, ServerError{
+					Status:  n,
+					Err:     err,
+					Panic:   panicFields != nil,
+					Message: msg,
+					Fields:  fields,
+				})
+			}
+
+			if config.HTTPRequestKey != "" {
+				buf.all = fields
+			} else {
+				buf.http = fields
+			}
+			putFields(buf)
+			return err
+		}
+	}, closeFunc
+}
+
+// defaultLogger builds the logger of a config without one.
+func defaultLogger(config Config) *zap.Logger {
+	if config.Level == (zap.AtomicLevel{}) {
+		config.Level = zap.NewAtomicLevel()
+	}
+	return NewLogger(config.Level)
+}
+
+// Messages are the access log messages per status class.
+type Messages struct {
+	Started     string
+	ServerError string
+	ClientError string
+	Redirection string
+	Success     string
+}
+
+// DefaultMessages are the messages used for those left empty in
+// Config.Messages.
+var DefaultMessages = Messages{
+	Started:     "Request started",
+	ServerError: "Server error",
+	ClientError: "Client error",
+	Redirection: "Redirection",
+	Success:     "Success",
+}
+
+func (m Messages) withDefaults() Messages {
+	d := DefaultMessages
+	setDefault(&m.Started, d.Started)
+	setDefault(&m.ServerError, d.ServerError)
+	setDefault(&m.ClientError, d.ClientError)
+	setDefault(&m.Redirection, d.Redirection)
+	setDefault(&m.Success, d.Success)
+	return m
+}
+
+// message returns the message of the status class.
+func (m Messages) message(status int) string {
+	switch {
+	case status >= 500:
+		return m.ServerError
+	case status >= 400:
+		return m.ClientError
+	case status >= 300:
+		return m.Redirection
+	default:
+		return m.Success
+	}
+}
+
+// DefaultLevelFunc logs 5xx responses at Error, 4xx at Warn and others at
+// Info.
+func DefaultLevelFunc(status int, err error) zapcore.Level {
+	switch {
+	case status >= 500:
+		return zapcore.ErrorLevel
+	case status >= 400:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// isPreflight reports whether the request is a CORS preflight request.
+func isPreflight(c echo.Context) bool {
+	req := c.Request()
+	return req.Method == http.MethodOptions && req.Header.Get(echo.HeaderAccessControlRequestMethod) != ""
+}
+
+// responseStatus returns the status the client receives for the request.
+// When the error has not been handled yet the response still carries the
+// default status, so the status is derived from the error instead.
+func responseStatus(c echo.Context, err error, handled bool) int {
+	res := c.Response()
+	if err == nil || handled || res.Committed {
+		return res.Status
+	}
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// unitSuffix returns the conventional abbreviation of a latency unit.
+func unitSuffix(unit time.Duration) string {
+	switch unit {
+	case time.Nanosecond:
+		return "ns"
+	case time.Microsecond:
+		return "us"
+	case time.Millisecond:
+		return "ms"
+	case time.Second:
+		return "s"
+	default:
+		return unit.String()
+	}
+}
+
+// fieldsMarshaler logs a list of fields as an object.
+type fieldsMarshaler []zapcore.Field
+
+func (fs fieldsMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range fs {
+		f.AddTo(enc)
+	}
+	return nil
+}
+
+// accessFields are the reusable field slices of an access log entry: the
+// HTTP fields and, when they are grouped, the entry's fields.
+type accessFields struct {
+	http []zapcore.Field
+	all  []zapcore.Field
+}
+
+var fieldsPool = sync.Pool{
+	New: func() interface{} {
+		return &accessFields{
+			http: make([]zapcore.Field, 0, 32),
+			all:  make([]zapcore.Field, 0, 16),
+		}
+	},
+}
+
+func getFields() *accessFields {
+	return fieldsPool.Get().(*accessFields)
+}
+
+// putFields returns f to the pool, without holding on to the values of
+// the request.
+func putFields(f *accessFields) {
+	clear(f.http)
+	clear(f.all)
+	f.http, f.all = f.http[:0], f.all[:0]
+	fieldsPool.Put(f)
+}
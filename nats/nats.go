@@ -0,0 +1,80 @@
+// Package nats provides a logger.Publisher publishing to NATS subjects,
+// optionally persisted by JetStream.
+package nats
+
+import (
+	"context"
+
+	logger "github.com/glepnir/zapecho"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// HeaderKey is the message header carrying the message key.
+const HeaderKey = "Log-Key"
+
+// Publisher publishes messages to the subject given by their topic.
+type Publisher struct {
+	nc *nats.Conn
+	js jetstream.JetStream
+}
+
+var _ logger.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns a Publisher publishing with core NATS, which does
+// not persist messages.
+func NewPublisher(nc *nats.Conn) *Publisher {
+	return &Publisher{nc: nc}
+}
+
+// NewJetStreamPublisher returns a Publisher publishing to JetStream, which
+// acknowledges messages once they are stored by a stream capturing their
+// subject.
+func NewJetStreamPublisher(nc *nats.Conn) (*Publisher, error) {
+	js, err := jetstream.New(nc)
+	if err != nil {
+		return nil, err
+	}
+	return &Publisher{nc: nc, js: js}, nil
+}
+
+// Publish publishes msgs, and waits for them to be flushed to the server
+// or acknowledged by JetStream.
+func (p *Publisher) Publish(ctx context.Context, msgs []logger.Message) error {
+	if p.js == nil {
+		for _, m := range msgs {
+			if err := p.nc.PublishMsg(natsMsg(m)); err != nil {
+				return err
+			}
+		}
+		return p.nc.FlushWithContext(ctx)
+	}
+
+	acks := make([]jetstream.PubAckFuture, 0, len(msgs))
+	for _, m := range msgs {
+		ack, err := p.js.PublishMsgAsync(natsMsg(m))
+		if err != nil {
+			return err
+		}
+		acks = append(acks, ack)
+	}
+	for _, ack := range acks {
+		select {
+		case <-ack.Ok():
+		case err := <-ack.Err():
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func natsMsg(m logger.Message) *nats.Msg {
+	msg := nats.NewMsg(m.Topic)
+	msg.Data = m.Value
+	if m.Key != "" {
+		msg.Header.Set(HeaderKey, m.Key)
+	}
+	return msg
+}
@@ -0,0 +1,158 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option configures a logger built by NewLoggerWithOptions.
+type Option func(*options)
+
+type options struct {
+	encoding        string
+	outputPaths     []string
+	caller          *bool
+	stacktraceLevel *zapcore.Level
+
+	sampling    *zap.SamplingConfig
+	setSampling bool
+	zapOptions  []zap.Option
+	outputs     []zapcore.WriteSyncer
+	split       *splitOutput
+	buffering   *buffering
+
+	replaceGlobals bool
+	undoGlobals    *func()
+}
+
+// WithSampling samples entries as zap's SamplerCore does: per second, the
+// first initial entries with the same level and message are logged, then
+// only every thereafter-th one.
+func WithSampling(initial, thereafter int) Option {
+	return func(o *options) {
+		o.sampling = &zap.SamplingConfig{Initial: initial, Thereafter: thereafter}
+		o.setSampling = true
+	}
+}
+
+// WithoutSampling disables sampling, which the production configuration
+// enables by default.
+func WithoutSampling() Option {
+	return func(o *options) {
+		o.sampling = nil
+		o.setSampling = true
+	}
+}
+
+// WithEncoding sets the encoding of a logger, "json" or "console". In JSON,
+// the levels of the development configuration are written without color
+// codes.
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithOutputPaths sets the output paths of a logger, in replacement of
+// its default output, as zap.Config.OutputPaths.
+func WithOutputPaths(paths ...string) Option {
+	return func(o *options) {
+		o.outputPaths = paths
+	}
+}
+
+// WithCallerEnabled enables or disables annotating entries with the file
+// and line of their caller.
+func WithCallerEnabled(enabled bool) Option {
+	return func(o *options) {
+		o.caller = &enabled
+	}
+}
+
+// WithStacktraceLevel records a stacktrace for entries at or above lv.
+func WithStacktraceLevel(lv zapcore.Level) Option {
+	return func(o *options) {
+		o.stacktraceLevel = &lv
+	}
+}
+
+// WithZapOptions passes opts to zap.Config.Build.
+func WithZapOptions(opts ...zap.Option) Option {
+	return func(o *options) {
+		o.zapOptions = append(o.zapOptions, opts...)
+	}
+}
+
+// WithOutput tees a logger's entries to ws, in the logger's encoding, next
+// to its output paths.
+func WithOutput(ws zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.outputs = append(o.outputs, ws)
+	}
+}
+
+// apply applies the options to c and returns the zap.Options to build it
+// with.
+func (o *options) apply(c *zap.Config) ([]zap.Option, error) {
+	if o.setSampling {
+		c.Sampling = o.sampling
+	}
+	if o.encoding != "" {
+		c.Encoding = o.encoding
+		if c.Encoding == "json" && c.Development {
+			c.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+		}
+	}
+	if o.outputPaths != nil {
+		c.OutputPaths = o.outputPaths
+	}
+	if o.caller != nil {
+		c.DisableCaller = !*o.caller
+	}
+	var zapOpts []zap.Option
+	if o.stacktraceLevel != nil {
+		zapOpts = append(zapOpts, zap.AddStacktrace(*o.stacktraceLevel))
+	}
+	switch {
+	case o.split != nil:
+		zapOpts = append(zapOpts, replaceCore(c, o.split.core(c, o.buffering)))
+	case o.buffering != nil:
+		ws, _, err := zap.Open(c.OutputPaths...)
+		if err != nil {
+			return nil, err
+		}
+		core := zapcore.NewCore(newEncoder(c), o.buffering.wrap(ws), c.Level)
+		zapOpts = append(zapOpts, replaceCore(c, core))
+	}
+	if len(o.outputs) > 0 {
+		ws := o.buffering.wrap(zapcore.NewMultiWriteSyncer(o.outputs...))
+		core := zapcore.NewCore(newEncoder(c), ws, c.Level)
+		zapOpts = append(zapOpts, zap.WrapCore(func(inner zapcore.Core) zapcore.Core {
+			return zapcore.NewTee(inner, core)
+		}))
+	}
+	return append(zapOpts, o.zapOptions...), nil
+}
+
+// replaceCore returns the zap.Option replacing the core of a logger built
+// from c by core, sampled as c configures. The output paths of c are
+// cleared, so building it opens no sink the replaced core would leak.
+func replaceCore(c *zap.Config, core zapcore.Core) zap.Option {
+	c.OutputPaths = nil
+	if c.Sampling != nil {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, c.Sampling.Initial, c.Sampling.Thereafter)
+	}
+	return zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return core
+	})
+}
+
+// newEncoder returns the encoder of c.
+func newEncoder(c *zap.Config) zapcore.Encoder {
+	if c.Encoding == "console" {
+		return zapcore.NewConsoleEncoder(c.EncoderConfig)
+	}
+	return zapcore.NewJSONEncoder(c.EncoderConfig)
+}
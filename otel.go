@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// OpenTelemetry extracts the trace context of the span an OpenTelemetry
+// instrumenting middleware, such as otelecho, stored in the request context.
+func OpenTelemetry(c echo.Context) (TraceContext, bool) {
+	sc := trace.SpanContextFromContext(c.Request().Context())
+	if !sc.IsValid() {
+		return TraceContext{}, false
+	}
+	return TraceContext{
+		TraceID: sc.TraceID().String(),
+		SpanID:  sc.SpanID().String(),
+		Flags:   sc.TraceFlags().String(),
+	}, true
+}
+
+// addSpanEvent records the access log entry as an event on the span in the
+// request context.
+func addSpanEvent(c echo.Context, msg string, fields []zapcore.Field) {
+	span := trace.SpanFromContext(c.Request().Context())
+	if !span.IsRecording() {
+		return
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	keys := make([]string, 0, len(enc.Fields))
+	for k := range enc.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]attribute.KeyValue, 0, len(keys))
+	for _, k := range keys {
+		switch v := enc.Fields[k].(type) {
+		case string:
+			attrs = append(attrs, attribute.String(k, v))
+		case bool:
+			attrs = append(attrs, attribute.Bool(k, v))
+		case int64:
+			attrs = append(attrs, attribute.Int64(k, v))
+		case int:
+			attrs = append(attrs, attribute.Int(k, v))
+		case float64:
+			attrs = append(attrs, attribute.Float64(k, v))
+		default:
+			attrs = append(attrs, attribute.String(k, fmt.Sprint(v)))
+		}
+	}
+	span.AddEvent(msg, trace.WithAttributes(attrs...))
+}
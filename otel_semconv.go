@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+)
+
+// OTelFieldNames names access log fields after the OpenTelemetry HTTP
+// semantic conventions, so logs share attribute names with metrics and
+// traces. See https://opentelemetry.io/docs/specs/semconv/http/.
+var OTelFieldNames = FieldNames{
+	RemoteIP:     "client.address",
+	Latency:      "http.server.request.duration",
+	Host:         "server.address",
+	HostPort:     "server.port",
+	Status:       "http.response.status_code",
+	Size:         "http.response.body.size",
+	BytesIn:      "http.request.body.size",
+	UserAgent:    "user_agent.original",
+	RequestID:    "http.request.id",
+	Method:       "http.request.method",
+	URI:          "url.full", // absolute, with Config.AbsoluteURI
+	Path:         "url.path",
+	Route:        "http.route",
+	PeerAddress:  "network.peer.address",
+	PeerPort:     "network.peer.port",
+	ForwardedFor: "http.request.header.x-forwarded-for",
+	TraceID:      "trace_id",
+	SpanID:       "span_id",
+	TraceFlags:   "trace_flags",
+}
+
+// NewOTelMiddlewareConfig returns a middleware config logging access
+// entries with OTelFieldNames.
+func NewOTelMiddlewareConfig(lv zap.AtomicLevel) Config {
+	c := DefaultConfig
+	c.Level = lv
+	c.FieldNames = OTelFieldNames
+	c.AbsoluteURI = true
+	c.LogPath = true
+	c.LogForwardedFor = true
+
+	return c
+}
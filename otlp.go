@@ -0,0 +1,273 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LogRecord is an entry converted to the OpenTelemetry log data model.
+type LogRecord struct {
+	Time  time.Time
+	Level zapcore.Level
+	Body  string
+	// Scope is the logger name, the instrumentation scope of the record.
+	Scope      string
+	Attributes map[string]interface{}
+	TraceID    string
+	SpanID     string
+}
+
+// SeverityNumber returns the OpenTelemetry severity number of the record.
+func (r LogRecord) SeverityNumber() int {
+	switch r.Level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	case zapcore.DPanicLevel:
+		return 18
+	case zapcore.PanicLevel:
+		return 21
+	default:
+		return 24
+	}
+}
+
+// LogExporter exports log records with resource attributes. The otlpgrpc
+// subpackage provides an OTLP/gRPC exporter, NewOTLPHTTPExporter an
+// OTLP/HTTP one.
+type LogExporter interface {
+	Export(ctx context.Context, resource map[string]string, records []LogRecord) error
+}
+
+// OTLP configures an OpenTelemetry log core.
+type OTLP struct {
+	// Resource are the resource attributes, e.g. {"service.name": "api"}.
+	Resource map[string]string
+
+	// TraceIDField and SpanIDField are the keys of the fields moved to the
+	// trace context of records, correlating them with traces.
+	// Optional. Default values DefaultFieldNames.TraceID and SpanID.
+	TraceIDField string
+	SpanIDField  string
+
+	// Timeout bounds an export.
+	// Optional. Default value 10s.
+	Timeout time.Duration
+
+	Batching Batching
+}
+
+// NewOTLPCore returns a core converting entries into OpenTelemetry log
+// records exported by exp in batches.
+func NewOTLPCore(exp LogExporter, o OTLP, enab zapcore.LevelEnabler) *BatchCore {
+	if o.TraceIDField == "" {
+		o.TraceIDField = DefaultFieldNames.TraceID
+	}
+	if o.SpanIDField == "" {
+		o.SpanIDField = DefaultFieldNames.SpanID
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+
+	encode := func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		m := zapcore.NewMapObjectEncoder()
+		for _, f := range fields {
+			f.AddTo(m)
+		}
+		r := LogRecord{
+			Time:       ent.Time,
+			Level:      ent.Level,
+			Body:       ent.Message,
+			Scope:      ent.LoggerName,
+			Attributes: m.Fields,
+		}
+		if id, ok := m.Fields[o.TraceIDField].(string); ok {
+			r.TraceID = id
+			delete(m.Fields, o.TraceIDField)
+		}
+		if id, ok := m.Fields[o.SpanIDField].(string); ok {
+			r.SpanID = id
+			delete(m.Fields, o.SpanIDField)
+		}
+		if ent.Caller.Defined {
+			m.Fields["code.filepath"] = ent.Caller.File
+			m.Fields["code.lineno"] = ent.Caller.Line
+		}
+		if ent.Stack != "" {
+			m.Fields["exception.stacktrace"] = ent.Stack
+		}
+		// The size only bounds batches, an estimate is enough.
+		return batchEntry{time: ent.Time, value: r, valueSize: len(ent.Message) + 64*len(m.Fields)}, nil
+	}
+
+	send := func(batch []batchEntry) error {
+		records := make([]LogRecord, len(batch))
+		for i, e := range batch {
+			records[i] = e.value.(LogRecord)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
+		defer cancel()
+		return exp.Export(ctx, o.Resource, records)
+	}
+
+	return newBatchCore(enab, o.Batching, encode, send)
+}
+
+// otlpHTTPExporter exports records as OTLP/HTTP JSON.
+type otlpHTTPExporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewOTLPHTTPExporter returns a LogExporter posting OTLP/HTTP JSON to the
+// logs endpoint of a collector, e.g. "http://collector:4318/v1/logs".
+// The headers are added to every request, e.g. for authentication.
+func NewOTLPHTTPExporter(url string, headers map[string]string, client *http.Client) LogExporter {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &otlpHTTPExporter{url: url, headers: headers, client: client}
+}
+
+func (e *otlpHTTPExporter) Export(ctx context.Context, resource map[string]string, records []LogRecord) error {
+	body, err := json.Marshal(otlpRequest(resource, records))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+	return doHTTP(e.client, req)
+}
+
+// otlpRequest returns the OTLP JSON ExportLogsServiceRequest of records,
+// grouped by scope.
+func otlpRequest(resource map[string]string, records []LogRecord) map[string]interface{} {
+	attrs := make([]map[string]interface{}, 0, len(resource))
+	for _, k := range sortedKeys(resource) {
+		attrs = append(attrs, otlpKeyValue(k, resource[k]))
+	}
+
+	var scopes []map[string]interface{}
+	byScope := make(map[string]int)
+	for _, r := range records {
+		i, ok := byScope[r.Scope]
+		if !ok {
+			i = len(scopes)
+			byScope[r.Scope] = i
+			scopes = append(scopes, map[string]interface{}{
+				"scope":      map[string]interface{}{"name": r.Scope},
+				"logRecords": []map[string]interface{}{},
+			})
+		}
+		scopes[i]["logRecords"] = append(scopes[i]["logRecords"].([]map[string]interface{}), otlpRecord(r))
+	}
+
+	return map[string]interface{}{
+		"resourceLogs": []map[string]interface{}{{
+			"resource":  map[string]interface{}{"attributes": attrs},
+			"scopeLogs": scopes,
+		}},
+	}
+}
+
+func otlpRecord(r LogRecord) map[string]interface{} {
+	keys := make([]string, 0, len(r.Attributes))
+	for k := range r.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]map[string]interface{}, len(keys))
+	for i, k := range keys {
+		attrs[i] = otlpKeyValue(k, r.Attributes[k])
+	}
+
+	rec := map[string]interface{}{
+		"timeUnixNano":   strconv.FormatInt(r.Time.UnixNano(), 10),
+		"severityNumber": r.SeverityNumber(),
+		"severityText":   strings.ToUpper(r.Level.String()),
+		"body":           map[string]interface{}{"stringValue": r.Body},
+		"attributes":     attrs,
+	}
+	if isHex(r.TraceID, 32) && !isZero(r.TraceID) {
+		rec["traceId"] = r.TraceID
+	}
+	if isHex(r.SpanID, 16) && !isZero(r.SpanID) {
+		rec["spanId"] = r.SpanID
+	}
+	return rec
+}
+
+func otlpKeyValue(k string, v interface{}) map[string]interface{} {
+	return map[string]interface{}{"key": k, "value": otlpAnyValue(v)}
+}
+
+// otlpAnyValue returns v as an OTLP JSON AnyValue.
+func otlpAnyValue(v interface{}) map[string]interface{} {
+	switch v := v.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return map[string]interface{}{"intValue": fmt.Sprint(v)}
+	case float32, float64:
+		return map[string]interface{}{"doubleValue": v}
+	case []byte:
+		return map[string]interface{}{"bytesValue": base64.StdEncoding.EncodeToString(v)}
+	case time.Duration:
+		return map[string]interface{}{"intValue": strconv.FormatInt(int64(v), 10)}
+	case time.Time:
+		return map[string]interface{}{"stringValue": v.Format(time.RFC3339Nano)}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]map[string]interface{}, len(keys))
+		for i, k := range keys {
+			values[i] = otlpKeyValue(k, v[k])
+		}
+		return map[string]interface{}{"kvlistValue": map[string]interface{}{"values": values}}
+	case []interface{}:
+		values := make([]map[string]interface{}, len(v))
+		for i, e := range v {
+			values[i] = otlpAnyValue(e)
+		}
+		return map[string]interface{}{"arrayValue": map[string]interface{}{"values": values}}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
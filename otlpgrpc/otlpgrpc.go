@@ -0,0 +1,165 @@
+// Package otlpgrpc provides a logger.LogExporter exporting OpenTelemetry
+// log records over OTLP/gRPC.
+package otlpgrpc
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	logger "github.com/glepnir/zapecho"
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Exporter exports records to the logs service of a collector.
+type Exporter struct {
+	client  collogs.LogsServiceClient
+	headers metadata.MD
+}
+
+var _ logger.LogExporter = (*Exporter)(nil)
+
+// NewExporter returns an Exporter sending on conn, created with
+// grpc.NewClient, e.g. for "collector:4317". The headers are sent as
+// metadata with every export, e.g. for authentication.
+func NewExporter(conn grpc.ClientConnInterface, headers map[string]string) *Exporter {
+	return &Exporter{client: collogs.NewLogsServiceClient(conn), headers: metadata.New(headers)}
+}
+
+// Export exports records, grouped by scope.
+func (e *Exporter) Export(ctx context.Context, resource map[string]string, records []logger.LogRecord) error {
+	if len(e.headers) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, e.headers)
+	}
+
+	res := &resourcepb.Resource{}
+	keys := make([]string, 0, len(resource))
+	for k := range resource {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		res.Attributes = append(res.Attributes, keyValue(k, resource[k]))
+	}
+
+	var scopes []*logspb.ScopeLogs
+	byScope := make(map[string]*logspb.ScopeLogs)
+	for _, r := range records {
+		s, ok := byScope[r.Scope]
+		if !ok {
+			s = &logspb.ScopeLogs{Scope: &commonpb.InstrumentationScope{Name: r.Scope}}
+			byScope[r.Scope] = s
+			scopes = append(scopes, s)
+		}
+		s.LogRecords = append(s.LogRecords, logRecord(r))
+	}
+
+	resp, err := e.client.Export(ctx, &collogs.ExportLogsServiceRequest{
+		ResourceLogs: []*logspb.ResourceLogs{{Resource: res, ScopeLogs: scopes}},
+	})
+	if err != nil {
+		return err
+	}
+	if p := resp.GetPartialSuccess(); p != nil && p.GetRejectedLogRecords() > 0 {
+		return fmt.Errorf("otlpgrpc: %d log records rejected: %s", p.GetRejectedLogRecords(), p.GetErrorMessage())
+	}
+	return nil
+}
+
+func logRecord(r logger.LogRecord) *logspb.LogRecord {
+	rec := &logspb.LogRecord{
+		TimeUnixNano:   uint64(r.Time.UnixNano()),
+		SeverityNumber: logspb.SeverityNumber(r.SeverityNumber()),
+		SeverityText:   strings.ToUpper(r.Level.String()),
+		Body:           anyValue(r.Body),
+	}
+	keys := make([]string, 0, len(r.Attributes))
+	for k := range r.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rec.Attributes = append(rec.Attributes, keyValue(k, r.Attributes[k]))
+	}
+	if id, err := hex.DecodeString(r.TraceID); err == nil && len(id) == 16 {
+		rec.TraceId = id
+	}
+	if id, err := hex.DecodeString(r.SpanID); err == nil && len(id) == 8 {
+		rec.SpanId = id
+	}
+	return rec
+}
+
+func keyValue(k string, v interface{}) *commonpb.KeyValue {
+	return &commonpb.KeyValue{Key: k, Value: anyValue(v)}
+}
+
+func anyValue(v interface{}) *commonpb.AnyValue {
+	switch v := v.(type) {
+	case string:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: v}}
+	case bool:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BoolValue{BoolValue: v}}
+	case int:
+		return intValue(int64(v))
+	case int8:
+		return intValue(int64(v))
+	case int16:
+		return intValue(int64(v))
+	case int32:
+		return intValue(int64(v))
+	case int64:
+		return intValue(v)
+	case uint:
+		return intValue(int64(v))
+	case uint8:
+		return intValue(int64(v))
+	case uint16:
+		return intValue(int64(v))
+	case uint32:
+		return intValue(int64(v))
+	case uint64:
+		return intValue(int64(v))
+	case float32:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_DoubleValue{DoubleValue: v}}
+	case []byte:
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_BytesValue{BytesValue: v}}
+	case time.Duration:
+		return intValue(int64(v))
+	case time.Time:
+		return anyValue(v.Format(time.RFC3339Nano))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		kvs := &commonpb.KeyValueList{}
+		for _, k := range keys {
+			kvs.Values = append(kvs.Values, keyValue(k, v[k]))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_KvlistValue{KvlistValue: kvs}}
+	case []interface{}:
+		arr := &commonpb.ArrayValue{}
+		for _, e := range v {
+			arr.Values = append(arr.Values, anyValue(e))
+		}
+		return &commonpb.AnyValue{Value: &commonpb.AnyValue_ArrayValue{ArrayValue: arr}}
+	default:
+		return anyValue(fmt.Sprint(v))
+	}
+}
+
+func intValue(i int64) *commonpb.AnyValue {
+	return &commonpb.AnyValue{Value: &commonpb.AnyValue_IntValue{IntValue: i}}
+}
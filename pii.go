@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Scrubber masks personal data in a logged string value.
+type Scrubber func(value string) string
+
+// RegexpScrubber returns a Scrubber replacing the matches of re with
+// replacement, which may refer to submatches as in regexp.ReplaceAllString.
+func RegexpScrubber(re *regexp.Regexp, replacement string) Scrubber {
+	return func(v string) string {
+		return re.ReplaceAllString(v, replacement)
+	}
+}
+
+var (
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	creditCardPattern = regexp.MustCompile(`\b(?:\d[ \-]?){12,18}\d\b`)
+	ssnPattern        = regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+)
+
+// Built-in scrubbers.
+var (
+	// ScrubEmails masks email addresses.
+	ScrubEmails = RegexpScrubber(emailPattern, RedactionMarker)
+
+	// ScrubCreditCards masks digit sequences that pass the Luhn check.
+	ScrubCreditCards Scrubber = func(v string) string {
+		return creditCardPattern.ReplaceAllStringFunc(v, func(m string) string {
+			if luhn(m) {
+				return RedactionMarker
+			}
+			return m
+		})
+	}
+
+	// ScrubSSNs masks US social security numbers.
+	ScrubSSNs = RegexpScrubber(ssnPattern, RedactionMarker)
+)
+
+// DefaultScrubbers are the scrubbers used by WithScrubbers when none are
+// given.
+var DefaultScrubbers = []Scrubber{ScrubEmails, ScrubCreditCards, ScrubSSNs}
+
+// luhn reports whether the digits of s pass the Luhn checksum.
+func luhn(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
+
+// fieldScrubber runs scrubbers over the string fields selected by key.
+type fieldScrubber struct {
+	scrubbers []Scrubber
+	// keys selects the scrubbed fields, all string fields when nil.
+	keys map[string]struct{}
+}
+
+func newFieldScrubber(scrubbers []Scrubber, keys []string) *fieldScrubber {
+	if len(scrubbers) == 0 {
+		return nil
+	}
+	s := &fieldScrubber{scrubbers: scrubbers}
+	if len(keys) > 0 {
+		s.keys = make(map[string]struct{}, len(keys))
+		for _, k := range keys {
+			s.keys[k] = struct{}{}
+		}
+	}
+	return s
+}
+
+// scrub masks the selected fields in place.
+func (s *fieldScrubber) scrub(fields []zapcore.Field) {
+	if s == nil {
+		return
+	}
+	for i, f := range fields {
+		if s.keys != nil {
+			if _, ok := s.keys[f.Key]; !ok {
+				continue
+			}
+		}
+		switch f.Type {
+		case zapcore.StringType:
+			fields[i].String = s.scrubString(f.String)
+		case zapcore.ByteStringType:
+			fields[i] = zap.String(f.Key, s.scrubString(string(f.Interface.([]byte))))
+		}
+	}
+}
+
+func (s *fieldScrubber) scrubString(v string) string {
+	for _, scrub := range s.scrubbers {
+		v = scrub(v)
+	}
+	return v
+}
+
+// scrubCore scrubs the fields of every entry written to the wrapped core.
+type scrubCore struct {
+	zapcore.Core
+	s *fieldScrubber
+}
+
+// WithScrubbers wraps a logger's core so the string fields named by keys,
+// or all string fields when keys is empty, are scrubbed before being
+// written. Nested objects and the message are not scrubbed.
+func WithScrubbers(keys []string, scrubbers ...Scrubber) zap.Option {
+	if len(scrubbers) == 0 {
+		scrubbers = DefaultScrubbers
+	}
+	s := newFieldScrubber(scrubbers, keys)
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &scrubCore{Core: core, s: s}
+	})
+}
+
+func (c *scrubCore) With(fields []zapcore.Field) zapcore.Core {
+	fields = append([]zapcore.Field(nil), fields...)
+	c.s.scrub(fields)
+	return &scrubCore{Core: c.Core.With(fields), s: c.s}
+}
+
+func (c *scrubCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *scrubCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	fields = append([]zapcore.Field(nil), fields...)
+	c.s.scrub(fields)
+	// Check again so level filters and samplers of the wrapped core apply.
+	ce := c.Core.Check(ent, nil)
+	if ce == nil {
+		return nil
+	}
+	var errs writeErrors
+	ce.ErrorOutput = &errs
+	ce.Write(fields...)
+	return errs.err
+}
+
+// writeErrors captures the write errors a CheckedEntry reports to its
+// ErrorOutput, as "<time> write error: <err>".
+type writeErrors struct {
+	err error
+}
+
+func (w *writeErrors) Write(p []byte) (int, error) {
+	_, msg, _ := strings.Cut(strings.TrimSpace(string(p)), "write error: ")
+	w.err = errors.Join(w.err, errors.New(msg))
+	return len(p), nil
+}
+
+func (w *writeErrors) Sync() error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Privacy configures the privacy mode of the middleware, for jurisdictions
+// where client IPs and user agents are personal data. In privacy mode the
+// remote IP and user agent are hashed, or dropped when no key is
+// available, query strings are removed and request IDs truncated.
+type Privacy struct {
+	// Key returns the HMAC-SHA256 key the remote IP and user agent are
+	// hashed with. It is called for every request, so a rotating key keeps
+	// hashes linkable only while the key is in use. When Key is nil or
+	// returns no key the fields are dropped.
+	Key func() []byte
+
+	// RequestIDLength is the number of request ID characters logged.
+	// Optional. Default value 8, a negative value logs none.
+	RequestIDLength int
+}
+
+// DefaultPrivacy hashes with a key rotated daily.
+var DefaultPrivacy = Privacy{
+	Key:             NewRotatingKey(24 * time.Hour),
+	RequestIDLength: 8,
+}
+
+// NewRotatingKey returns a Privacy.Key generating a new random key every
+// period. Hashes cannot be linked across periods, or across restarts.
+func NewRotatingKey(period time.Duration) func() []byte {
+	var (
+		mu      sync.Mutex
+		key     []byte
+		expires time.Time
+	)
+	return func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		if now := time.Now(); key == nil || !now.Before(expires) {
+			key = make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				key = nil
+				return nil
+			}
+			expires = now.Truncate(period).Add(period)
+		}
+		return key
+	}
+}
+
+// anonymize returns the hashed value, or false when it must be dropped.
+func (p *Privacy) anonymize(v string) (string, bool) {
+	if p.Key == nil {
+		return "", false
+	}
+	key := p.Key()
+	if len(key) == 0 {
+		return "", false
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(v))
+	return hex.EncodeToString(mac.Sum(nil)[:16]), true
+}
+
+// requestID returns the truncated request ID.
+func (p *Privacy) requestID(id string) string {
+	n := p.RequestIDLength
+	if n == 0 {
+		n = DefaultPrivacy.RequestIDLength
+	}
+	if n < 0 {
+		return ""
+	}
+	if len(id) > n {
+		return id[:n]
+	}
+	return id
+}
+
+// stripQuery removes the query string from uri.
+func stripQuery(uri string) string {
+	path, _, _ := strings.Cut(uri, "?")
+	return path
+}
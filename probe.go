@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// DefaultProbePaths are the health and readiness probe paths detected
+// when Probes.Paths is empty.
+var DefaultProbePaths = []string{
+	"/healthz",
+	"/livez",
+	"/readyz",
+	"/health",
+	"/ready",
+	"/ping",
+}
+
+// DefaultProbeUserAgents are the user agent prefixes of probes detected
+// when Probes.UserAgents is empty.
+var DefaultProbeUserAgents = []string{
+	"kube-probe/",
+	"ELB-HealthChecker/",
+	"GoogleHC/",
+}
+
+// Probes configures the detection of health and readiness probes, which
+// dominate access logs in Kubernetes. Successful probe requests are logged
+// at Debug, or not at all with Drop. Failing probes are logged as usual.
+type Probes struct {
+	// Paths are the probe paths, with the syntax of Config.SkipPaths.
+	// Optional. Default value DefaultProbePaths.
+	Paths []string
+
+	// UserAgents are user agent prefixes of probes.
+	// Optional. Default value DefaultProbeUserAgents.
+	UserAgents []string
+
+	// Drop discards successful probe requests instead of logging them at
+	// Debug.
+	Drop bool
+}
+
+// probeMatcher detects probe requests.
+type probeMatcher struct {
+	paths      *pathMatcher
+	userAgents []string
+	drop       bool
+}
+
+func newProbeMatcher(p *Probes) *probeMatcher {
+	if p == nil {
+		return nil
+	}
+	paths, userAgents := p.Paths, p.UserAgents
+	if len(paths) == 0 {
+		paths = DefaultProbePaths
+	}
+	if len(userAgents) == 0 {
+		userAgents = DefaultProbeUserAgents
+	}
+	return &probeMatcher{
+		paths:      newPathMatcher(paths),
+		userAgents: userAgents,
+		drop:       p.Drop,
+	}
+}
+
+func (m *probeMatcher) match(c echo.Context) bool {
+	if m == nil {
+		return false
+	}
+	req := c.Request()
+	if m.paths.match(req.URL.Path) {
+		return true
+	}
+	ua := req.UserAgent()
+	for _, prefix := range m.userAgents {
+		if strings.HasPrefix(ua, prefix) {
+			return true
+		}
+	}
+	return false
+}
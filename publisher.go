@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Message is an encoded entry published to a message broker.
+type Message struct {
+	// Topic is the topic, subject, stream or exchange published to.
+	Topic string
+	// Key is the partitioning or routing key, empty when unkeyed.
+	Key   string
+	Value []byte
+	Time  time.Time
+}
+
+// Publisher publishes messages to a message broker or log service. The
+// kafka, nats, redisstream, amqp and cloudwatch subpackages provide
+// implementations. Publishers sending messages in several requests return
+// a *PublishError when some of them fail.
+type Publisher interface {
+	Publish(ctx context.Context, msgs []Message) error
+}
+
+// PublishError is returned by a Publisher when only part of the messages
+// could be published, so the others are not published again.
+type PublishError struct {
+	Err error
+	// Failed are the indexes of the messages not published.
+	Failed []int
+}
+
+func (e *PublishError) Error() string { return e.Err.Error() }
+func (e *PublishError) Unwrap() error { return e.Err }
+
+// Publishing configures a publisher core.
+type Publishing struct {
+	// Topic is the destination of the messages.
+	Topic string
+
+	// KeyField is the key of the field whose value keys the messages,
+	// e.g. "request_id" or "route", so related entries are kept in order.
+	// Optional. Default value "", unkeyed messages.
+	KeyField string
+
+	// Encoder encodes the message values.
+	// Optional. Default value a JSON encoder.
+	Encoder zapcore.Encoder
+
+	// Timeout bounds a publish.
+	// Optional. Default value 10s.
+	Timeout time.Duration
+
+	// OnFailure is called with the messages of failed publishes, which are
+	// retried with the next batch.
+	// Optional. Default value nil.
+	OnFailure func(msgs []Message, err error)
+
+	Batching Batching
+}
+
+// NewPublisherCore returns a core publishing entries through p in batches,
+// from a background goroutine.
+func NewPublisherCore(p Publisher, cfg Publishing, enab zapcore.LevelEnabler) *BatchCore {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	encodeValue := encodeJSON(cfg.Encoder)
+
+	encode := func(ent zapcore.Entry, fields []zapcore.Field) (batchEntry, error) {
+		e, err := encodeValue(ent, fields)
+		if err != nil || cfg.KeyField == "" {
+			return e, err
+		}
+		for _, f := range fields {
+			if f.Key == cfg.KeyField {
+				e.key = fieldString(f)
+			}
+		}
+		return e, nil
+	}
+
+	send := func(batch []batchEntry) error {
+		msgs := make([]Message, len(batch))
+		for i, e := range batch {
+			msgs[i] = Message{Topic: cfg.Topic, Key: e.key, Value: e.data, Time: e.time}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+		defer cancel()
+		err := p.Publish(ctx, msgs)
+		if err == nil {
+			return nil
+		}
+		var perr *PublishError
+		if errors.As(err, &perr) {
+			if cfg.OnFailure != nil {
+				failed := make([]Message, 0, len(perr.Failed))
+				for _, i := range perr.Failed {
+					failed = append(failed, msgs[i])
+				}
+				cfg.OnFailure(failed, err)
+			}
+			return partialError{err: err, failed: perr.Failed}
+		}
+		if cfg.OnFailure != nil {
+			cfg.OnFailure(msgs, err)
+		}
+		return err
+	}
+
+	return newBatchCore(enab, cfg.Batching, encode, send)
+}
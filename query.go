@@ -0,0 +1,90 @@
+package logger
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultSensitiveQueryParams are the query parameters masked by
+// DefaultQueryRedactor.
+var DefaultSensitiveQueryParams = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"password",
+	"secret",
+	"token",
+}
+
+// DefaultQueryRedactor masks DefaultSensitiveQueryParams.
+var DefaultQueryRedactor = func() *Redactor {
+	r := NewRedactor(DefaultSensitiveQueryParams...)
+	r.Mask = func(string) string { return RedactionMarker }
+	return r
+}()
+
+// redactURI masks the sensitive query parameter values of uri, keeping
+// the parameter order.
+func redactURI(uri string, r *Redactor) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	return path + "?" + redactQuery(query, r)
+}
+
+// queryValueEscaper escapes the characters of a masked value that would
+// change the structure of the query, leaving markers such as "****"
+// readable.
+var queryValueEscaper = strings.NewReplacer(
+	"%", "%25",
+	"&", "%26",
+	"#", "%23",
+	"+", "%2B",
+	" ", "+",
+)
+
+// redactQuery masks the sensitive parameter values of a raw query.
+func redactQuery(query string, r *Redactor) string {
+	if query == "" {
+		return query
+	}
+	parts := strings.Split(query, "&")
+	changed := false
+	for i, p := range parts {
+		k, v, _ := strings.Cut(p, "=")
+		if name, err := url.QueryUnescape(k); err == nil && r.Sensitive(name) {
+			if value, err := url.QueryUnescape(v); err == nil {
+				v = value
+			}
+			parts[i] = k + "=" + queryValueEscaper.Replace(r.Redact(name, v))
+			changed = true
+		}
+	}
+	if !changed {
+		return query
+	}
+	return strings.Join(parts, "&")
+}
+
+// queryMarshaler logs query parameters as an object, masking sensitive
+// values.
+type queryMarshaler struct {
+	query    url.Values
+	redactor *Redactor
+}
+
+func (m queryMarshaler) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m.query))
+	for k := range m.query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		enc.AddString(k, m.redactor.Redact(k, strings.Join(m.query[k], ",")))
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recoverNext calls next, recovering from panics. A recovered panic is
+// returned as an error, the panic value and stack as fields for the access
+// log entry. http.ErrAbortHandler is re-panicked so net/http aborts the
+// response.
+func recoverNext(next echo.HandlerFunc, c echo.Context, names FieldNames) (fields []zapcore.Field, err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		if r == http.ErrAbortHandler {
+			panic(r)
+		}
+
+		perr, ok := r.(error)
+		if !ok {
+			perr = fmt.Errorf("%v", r)
+		}
+		err = fmt.Errorf("[PANIC RECOVER] %w", perr)
+		fields = []zapcore.Field{
+			zap.Any(names.Panic, r),
+			zap.StackSkip(names.Stacktrace, 1),
+		}
+	}()
+
+	return nil, next(c)
+}
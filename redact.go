@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RedactionMarker replaces redacted values.
+const RedactionMarker = "****"
+
+// DefaultSensitiveHeaders are the headers masked by DefaultRedactor.
+var DefaultSensitiveHeaders = []string{
+	echo.HeaderAuthorization,
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// DefaultRedactor masks DefaultSensitiveHeaders.
+var DefaultRedactor = NewRedactor(DefaultSensitiveHeaders...)
+
+// Redactor masks the values of sensitive keys, such as header or query
+// parameter names, before they are logged. Keys are case-insensitive. A
+// Redactor is safe for concurrent use.
+type Redactor struct {
+	keys map[string]struct{}
+
+	// Mask returns the masked value.
+	// Optional. Default value MaskValue.
+	Mask func(value string) string
+}
+
+// NewRedactor returns a Redactor masking the given keys.
+func NewRedactor(keys ...string) *Redactor {
+	r := &Redactor{keys: make(map[string]struct{}, len(keys)), Mask: MaskValue}
+	for _, k := range keys {
+		r.keys[strings.ToLower(k)] = struct{}{}
+	}
+	return r
+}
+
+// With returns a copy of r that also masks the given keys.
+func (r *Redactor) With(keys ...string) *Redactor {
+	n := &Redactor{keys: make(map[string]struct{}, len(r.keys)+len(keys)), Mask: r.Mask}
+	for k := range r.keys {
+		n.keys[k] = struct{}{}
+	}
+	for _, k := range keys {
+		n.keys[strings.ToLower(k)] = struct{}{}
+	}
+	return n
+}
+
+// Sensitive reports whether the values of key are masked.
+func (r *Redactor) Sensitive(key string) bool {
+	if r == nil {
+		return false
+	}
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// Redact returns value, masked when key is sensitive.
+func (r *Redactor) Redact(key, value string) string {
+	if !r.Sensitive(key) {
+		return value
+	}
+	if r.Mask == nil {
+		return MaskValue(value)
+	}
+	return r.Mask(value)
+}
+
+// MaskValue replaces value with RedactionMarker, keeping a leading
+// authentication scheme such as "Bearer".
+func MaskValue(value string) string {
+	if scheme, _, ok := strings.Cut(value, " "); ok && scheme != "" {
+		return scheme + " " + RedactionMarker
+	}
+	return RedactionMarker
+}
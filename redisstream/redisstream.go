@@ -0,0 +1,46 @@
+// Package redisstream provides a logger.Publisher appending to Redis
+// streams.
+package redisstream
+
+import (
+	"context"
+
+	logger "github.com/glepnir/zapecho"
+	"github.com/redis/go-redis/v9"
+)
+
+// Publisher appends messages with XADD to the stream given by their topic,
+// as entries with an "entry" field and, for keyed messages, a "key" field.
+// Connection pooling and reconnects are handled by the client.
+type Publisher struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+var _ logger.Publisher = (*Publisher)(nil)
+
+// NewPublisher returns a Publisher appending with client. Streams are
+// approximately capped at maxLen entries, or unbounded when maxLen is 0.
+func NewPublisher(client redis.UniversalClient, maxLen int64) *Publisher {
+	return &Publisher{client: client, maxLen: maxLen}
+}
+
+// Publish appends msgs in a single pipeline.
+func (p *Publisher) Publish(ctx context.Context, msgs []logger.Message) error {
+	_, err := p.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, m := range msgs {
+			values := []interface{}{"entry", m.Value}
+			if m.Key != "" {
+				values = append(values, "key", m.Key)
+			}
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: m.Topic,
+				MaxLen: p.maxLen,
+				Approx: p.maxLen > 0,
+				Values: values,
+			})
+		}
+		return nil
+	})
+	return err
+}
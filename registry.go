@@ -0,0 +1,101 @@
+package logger
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Registry holds access log middleware configs for routes and route groups,
+// so different parts of an application can be logged differently with a
+// single middleware.
+//
+//	r := logger.NewRegistry(logger.DefaultConfig)
+//	r.Route("/debug/*", func(c *logger.Config) { c.LogRequestBody = true })
+//	r.Route("/static/*", func(c *logger.Config) { c.ErrorsOnly = true })
+//	e.Use(r.Middleware())
+type Registry struct {
+	def    Config
+	routes []registryRoute
+}
+
+type registryRoute struct {
+	pattern string
+	prefix  bool
+	config  Config
+}
+
+// NewRegistry returns a Registry logging requests whose route has no config
+// of its own with def.
+func NewRegistry(def Config) *Registry {
+	return &Registry{def: def}
+}
+
+// Route registers the config of pattern, which is either a route path as
+// registered with echo, such as "/users/:id", or a prefix ending in "/*"
+// matching a route group. The config is a copy of the default config
+// changed by override, so settings such as HandleError and the logger are
+// kept unless overridden. Without a Logger, a route overriding Level gets
+// a logger of its own at that level, the others share the default one.
+// The longest matching pattern wins. Route must not be called once
+// Middleware has been called.
+func (r *Registry) Route(pattern string, override func(c *Config)) *Registry {
+	config := r.def
+	override(&config)
+	rt := registryRoute{pattern: pattern, config: config}
+	if strings.HasSuffix(pattern, "*") {
+		rt.pattern, rt.prefix = strings.TrimSuffix(pattern, "*"), true
+	}
+	r.routes = append(r.routes, rt)
+	sort.SliceStable(r.routes, func(i, j int) bool {
+		return len(r.routes[i].pattern) > len(r.routes[j].pattern)
+	})
+	return r
+}
+
+// Middleware returns the access log middleware dispatching to the config
+// registered for each request's route.
+func (r *Registry) Middleware() echo.MiddlewareFunc {
+	defConfig := r.def
+	if defConfig.Logger == nil {
+		defConfig.Logger = defaultLogger(defConfig)
+	}
+	def := ZapMiddlewareWithConfig(defConfig)
+	mws := make([]echo.MiddlewareFunc, len(r.routes))
+	for i, rt := range r.routes {
+		config := rt.config
+		if config.Logger == nil && config.Level == r.def.Level {
+			config.Logger = defConfig.Logger
+		}
+		mws[i] = ZapMiddlewareWithConfig(config)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		defHandler := def(next)
+		handlers := make([]echo.HandlerFunc, len(mws))
+		for i, mw := range mws {
+			handlers[i] = mw(next)
+		}
+
+		return func(c echo.Context) error {
+			path := c.Path()
+			if path == "" {
+				path = c.Request().URL.Path
+			}
+			for i, rt := range r.routes {
+				if rt.match(path) {
+					return handlers[i](c)
+				}
+			}
+			return defHandler(c)
+		}
+	}
+}
+
+func (rt registryRoute) match(path string) bool {
+	if rt.prefix {
+		return strings.HasPrefix(path, rt.pattern)
+	}
+	return path == rt.pattern
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewUUID returns a random (version 4) UUID. It is the default
+// Config.RequestIDGenerator.
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Errorf("logging.NewUUID: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// requestID returns the request ID carried by the request or set on the
+// response by an earlier middleware such as echo's RequestID.
+func requestID(c echo.Context) string {
+	id := c.Request().Header.Get(echo.HeaderXRequestID)
+	if id == "" {
+		id = c.Response().Header().Get(echo.HeaderXRequestID)
+	}
+	return id
+}
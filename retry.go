@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Retry is a retry policy with exponential backoff.
+type Retry struct {
+	// MaxAttempts is the number of attempts, including the first.
+	// Optional. Default value 3.
+	MaxAttempts int
+
+	// Backoff is the wait before the second attempt, doubled after each
+	// further attempt up to MaxBackoff.
+	// Optional. Default values 100ms and 5s.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of each wait randomized, spreading the
+	// retries of many instances after a collector outage. A negative
+	// value disables it.
+	// Optional. Default value 0.2.
+	Jitter float64
+}
+
+func (r Retry) withDefaults() Retry {
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = 3
+	}
+	if r.Backoff <= 0 {
+		r.Backoff = 100 * time.Millisecond
+	}
+	if r.MaxBackoff <= 0 {
+		r.MaxBackoff = 5 * time.Second
+	}
+	if r.Jitter == 0 {
+		r.Jitter = 0.2
+	}
+	if r.Jitter < 0 {
+		r.Jitter = 0
+	}
+	return r
+}
+
+// do calls f until it succeeds, fails permanently or the attempts are
+// exhausted, and returns its last error.
+func (r Retry) do(f func() error) error {
+	r = r.withDefaults()
+	backoff := r.Backoff
+	for attempt := 1; ; attempt++ {
+		err := f()
+		var perr permanentError
+		if err == nil || attempt == r.MaxAttempts || errors.As(err, &perr) {
+			return err
+		}
+		time.Sleep(r.wait(backoff))
+		if backoff *= 2; backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
+}
+
+// wait returns backoff with jitter applied.
+func (r Retry) wait(backoff time.Duration) time.Duration {
+	j := r.Jitter * float64(backoff)
+	return backoff + time.Duration(j*(2*rand.Float64()-1))
+}
+
+// retryWriter retries failed writes.
+type retryWriter struct {
+	zapcore.WriteSyncer
+	retry Retry
+}
+
+// NewRetryWriter returns a zapcore.WriteSyncer retrying the failed writes
+// and syncs of ws with r. Writes block while retrying, wrap the result in
+// a BufferedWriter to keep them off the request path.
+func NewRetryWriter(ws zapcore.WriteSyncer, r Retry) zapcore.WriteSyncer {
+	return &retryWriter{WriteSyncer: ws, retry: r}
+}
+
+func (w *retryWriter) Write(p []byte) (int, error) {
+	var n int
+	err := w.retry.do(func() error {
+		var err error
+		n, err = w.WriteSyncer.Write(p)
+		return err
+	})
+	return n, err
+}
+
+func (w *retryWriter) Sync() error {
+	return w.retry.do(w.WriteSyncer.Sync)
+}
@@ -0,0 +1,38 @@
+// Package s3 provides a logger.Uploader storing objects in Amazon S3 or
+// S3-compatible storage.
+package s3
+
+import (
+	"context"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	logger "github.com/glepnir/zapecho"
+)
+
+// Uploader uploads objects to a bucket. For S3-compatible storage, create
+// the client with a custom endpoint and path-style addressing.
+type Uploader struct {
+	client *s3.Client
+	bucket string
+}
+
+var _ logger.Uploader = (*Uploader)(nil)
+
+// NewUploader returns an Uploader storing objects in bucket.
+func NewUploader(client *s3.Client, bucket string) *Uploader {
+	return &Uploader{client: client, bucket: bucket}
+}
+
+// Upload stores body under key.
+func (u *Uploader) Upload(ctx context.Context, key string, body io.Reader) error {
+	_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:          aws.String(u.bucket),
+		Key:             aws.String(key),
+		Body:            body,
+		ContentType:     aws.String("application/x-ndjson"),
+		ContentEncoding: aws.String("gzip"),
+	})
+	return err
+}
@@ -0,0 +1,92 @@
+// Package sentry reports the server errors of the access log middleware
+// to Sentry.
+package sentry
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	logger "github.com/glepnir/zapecho"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap/zapcore"
+)
+
+// Hook returns a Config.OnServerError hook capturing server errors and
+// panics with hub, or the current hub when nil. Events carry the request,
+// its method, route and status as tags and the access log fields as the
+// access_log context, and are fingerprinted by route, status and error
+// type, so they are grouped in Sentry. Events with a fingerprint captured
+// less than dedupe ago are not sent again.
+func Hook(hub *sentry.Hub, dedupe time.Duration) func(echo.Context, logger.ServerError) {
+	d := &deduper{window: dedupe, seen: make(map[string]time.Time)}
+
+	return func(c echo.Context, e logger.ServerError) {
+		h := hub
+		if h == nil {
+			h = sentry.CurrentHub()
+		}
+		fingerprint := []string{c.Request().Method, c.Path(), strconv.Itoa(e.Status), fmt.Sprintf("%T", e.Err)}
+		if !d.allow(fingerprint) {
+			return
+		}
+
+		h = h.Clone()
+		h.WithScope(func(scope *sentry.Scope) {
+			scope.SetRequest(c.Request())
+			scope.SetFingerprint(fingerprint)
+			scope.SetTag("method", c.Request().Method)
+			scope.SetTag("route", c.Path())
+			scope.SetTag("status", strconv.Itoa(e.Status))
+			m := zapcore.NewMapObjectEncoder()
+			for _, f := range e.Fields {
+				f.AddTo(m)
+			}
+			scope.SetContext("access_log", sentry.Context(m.Fields))
+
+			if e.Panic {
+				scope.SetLevel(sentry.LevelFatal)
+			} else {
+				scope.SetLevel(sentry.LevelError)
+			}
+			if e.Err != nil {
+				h.CaptureException(e.Err)
+			} else {
+				h.CaptureMessage(e.Message)
+			}
+		})
+	}
+}
+
+// deduper drops repeated fingerprints within a time window.
+type deduper struct {
+	window time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func (d *deduper) allow(fingerprint []string) bool {
+	if d.window <= 0 {
+		return true
+	}
+	key := fmt.Sprint(fingerprint)
+	now := time.Now()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if last, ok := d.seen[key]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	if len(d.seen) >= 1000 {
+		for k, t := range d.seen {
+			if now.Sub(t) >= d.window {
+				delete(d.seen, k)
+			}
+		}
+	}
+	d.seen[key] = now
+	return true
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"os"
+
+	"go.uber.org/zap"
+)
+
+// ServiceInfo identifies the deployment a logger runs in.
+type ServiceInfo struct {
+	Name        string
+	Environment string
+	Version     string
+	Commit      string
+}
+
+// WithServiceInfo adds the service, environment, version and commit fields
+// of info to every entry. Empty values are omitted.
+func WithServiceInfo(info ServiceInfo) Option {
+	return WithZapOptions(zap.Fields(info.Fields()...))
+}
+
+// Fields returns the non-empty values of info as fields, for loggers not
+// built by NewLoggerWithOptions.
+func (info ServiceInfo) Fields() []zap.Field {
+	fields := make([]zap.Field, 0, 4)
+	if info.Name != "" {
+		fields = append(fields, zap.String("service", info.Name))
+	}
+	if info.Environment != "" {
+		fields = append(fields, zap.String("environment", info.Environment))
+	}
+	if info.Version != "" {
+		fields = append(fields, zap.String("version", info.Version))
+	}
+	if info.Commit != "" {
+		fields = append(fields, zap.String("commit", info.Commit))
+	}
+	return fields
+}
+
+// WithHostAndPID adds the hostname and process ID to every entry, as
+// hostname and pid. The key is not host to avoid colliding with the
+// request host of access log entries.
+func WithHostAndPID() Option {
+	return WithZapOptions(zap.Fields(hostAndPIDFields()...))
+}
+
+func hostAndPIDFields() []zap.Field {
+	fields := []zap.Field{zap.Int("pid", os.Getpid())}
+	if host, err := os.Hostname(); err == nil {
+		fields = append([]zap.Field{zap.String("hostname", host)}, fields...)
+	}
+	return fields
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Session configures the SessionID enricher.
+type Session struct {
+	// Cookie is the name of the cookie carrying the session ID.
+	Cookie string
+
+	// ContextKey is the echo.Context key of the session ID, used when the
+	// cookie is not set. Values that are not strings are formatted with
+	// fmt.Sprint.
+	ContextKey string
+
+	// Hash logs the hex encoded SHA-256 of the session ID instead of the
+	// ID, so sessions can be followed without logging a credential.
+	Hash bool
+
+	// FieldName is the key of the field.
+	// Optional. Default value "session_id".
+	FieldName string
+}
+
+// SessionID returns an enricher, for Config.Enrichers, logging the session
+// identifier of a request.
+func SessionID(s Session) func(echo.Context) []zapcore.Field {
+	if s.FieldName == "" {
+		s.FieldName = "session_id"
+	}
+	return func(c echo.Context) []zapcore.Field {
+		var id string
+		if s.Cookie != "" {
+			if cookie, err := c.Cookie(s.Cookie); err == nil {
+				id = cookie.Value
+			}
+		}
+		if id == "" && s.ContextKey != "" {
+			if v := c.Get(s.ContextKey); v != nil {
+				id = fmt.Sprint(v)
+			}
+		}
+		if id == "" {
+			return nil
+		}
+		if s.Hash {
+			sum := sha256.Sum256([]byte(id))
+			id = hex.EncodeToString(sum[:])
+		}
+		return []zapcore.Field{zap.String(s.FieldName, id)}
+	}
+}
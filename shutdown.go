@@ -0,0 +1,97 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"syscall"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// closers are the sinks and middleware loggers with background goroutines
+// not closed yet, closed by Shutdown.
+var closers = &closerRegistry{closers: make(map[closer]struct{})}
+
+type closer interface {
+	Close() error
+}
+
+type closerRegistry struct {
+	mu      sync.Mutex
+	closers map[closer]struct{}
+}
+
+func (r *closerRegistry) add(c closer) {
+	r.mu.Lock()
+	r.closers[c] = struct{}{}
+	r.mu.Unlock()
+}
+
+func (r *closerRegistry) remove(c closer) {
+	r.mu.Lock()
+	delete(r.closers, c)
+	r.mu.Unlock()
+}
+
+func (r *closerRegistry) all() []closer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	all := make([]closer, 0, len(r.closers))
+	for c := range r.closers {
+		all = append(all, c)
+	}
+	return all
+}
+
+// Shutdown flushes the entries buffered by this package before the
+// process exits: it syncs loggers and the loggers of the middlewares,
+// sends and closes the batching sinks and stops the BufferedWriters. It
+// returns ctx.Err() when ctx is done first. Sync errors of terminals and
+// standard streams are ignored.
+func Shutdown(ctx context.Context, loggers ...*zap.Logger) error {
+	done := make(chan error, 1)
+	go func() {
+		var errs []error
+		for _, l := range loggers {
+			if err := l.Sync(); err != nil && !isUnsyncable(err) {
+				errs = append(errs, err)
+			}
+		}
+		for _, c := range closers.all() {
+			errs = append(errs, c.Close())
+		}
+		for _, w := range buffers.all() {
+			errs = append(errs, w.Stop())
+		}
+		done <- errors.Join(errs...)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ShutdownServer gracefully shuts down e, waiting for in-flight requests
+// to be served and logged, then calls Shutdown, e.g.
+//
+//	<-sigCtx.Done()
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	if err := logger.ShutdownServer(ctx, e, log); err != nil {
+//		e.Logger.Error(err)
+//	}
+func ShutdownServer(ctx context.Context, e *echo.Echo, loggers ...*zap.Logger) error {
+	err := e.Shutdown(ctx)
+	return errors.Join(err, Shutdown(ctx, loggers...))
+}
+
+// isUnsyncable reports whether err is the error of syncing a file that
+// cannot be synced, such as a terminal or pipe.
+func isUnsyncable(err error) bool {
+	return errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY)
+}
@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"path"
+	"strings"
+)
+
+// pathMatcher matches request paths against exact paths and globs.
+type pathMatcher struct {
+	exact    map[string]struct{}
+	prefixes []string
+	globs    []string
+}
+
+// newPathMatcher returns a matcher for patterns, or nil when there are
+// none. A pattern ending in "/*" matches everything below it, other
+// patterns use path.Match syntax.
+func newPathMatcher(patterns []string) *pathMatcher {
+	if len(patterns) == 0 {
+		return nil
+	}
+	m := &pathMatcher{exact: make(map[string]struct{})}
+	for _, p := range patterns {
+		switch {
+		case strings.HasSuffix(p, "/*"):
+			m.prefixes = append(m.prefixes, strings.TrimSuffix(p, "*"))
+		case strings.ContainsAny(p, `*?[\`):
+			m.globs = append(m.globs, p)
+		default:
+			m.exact[p] = struct{}{}
+		}
+	}
+	return m
+}
+
+func (m *pathMatcher) match(p string) bool {
+	if m == nil {
+		return false
+	}
+	if _, ok := m.exact[p]; ok {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(p, prefix) {
+			return true
+		}
+	}
+	for _, g := range m.globs {
+		if ok, _ := path.Match(g, p); ok {
+			return true
+		}
+	}
+	return false
+}
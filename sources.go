@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"net/netip"
+)
+
+// Sources configures the filtering of requests by the client IP, resolved
+// as the logged remote IP under Config.TrustedProxies, e.g. for health
+// checks of an internal load balancer. Successful requests from Prefixes
+// are logged at Debug, or not at all with Drop. Failing requests are
+// logged as usual.
+type Sources struct {
+	Prefixes []netip.Prefix
+
+	// Drop discards successful requests instead of logging them at Debug.
+	Drop bool
+}
+
+func (s *Sources) match(ip string) bool {
+	if s == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range s.Prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewSplitCore returns a core writing the entries enabled by enab below
+// threshold to low and the others to high, e.g. Info to stdout and Warn
+// and above to stderr.
+func NewSplitCore(enc zapcore.Encoder, low, high zapcore.WriteSyncer, enab zapcore.LevelEnabler, threshold zapcore.Level) zapcore.Core {
+	return zapcore.NewTee(
+		zapcore.NewCore(enc, low, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l < threshold && enab.Enabled(l)
+		})),
+		zapcore.NewCore(enc.Clone(), high, zap.LevelEnablerFunc(func(l zapcore.Level) bool {
+			return l >= threshold && enab.Enabled(l)
+		})),
+	)
+}
+
+// WithSplitOutput writes a logger's entries below Warn to low and the
+// others to high, instead of to its output paths. Files and standard
+// streams can be opened with zap.Open, e.g. zap.Open("access.log").
+func WithSplitOutput(low, high zapcore.WriteSyncer) Option {
+	return WithSplitOutputAt(zapcore.WarnLevel, low, high)
+}
+
+// WithSplitOutputAt is WithSplitOutput with a threshold other than Warn.
+func WithSplitOutputAt(threshold zapcore.Level, low, high zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.split = &splitOutput{threshold: threshold, low: low, high: high}
+	}
+}
+
+type splitOutput struct {
+	threshold zapcore.Level
+	low, high zapcore.WriteSyncer
+}
+
+// core returns the split core of a logger built from c.
+func (s *splitOutput) core(c *zap.Config, b *buffering) zapcore.Core {
+	return NewSplitCore(newEncoder(c), b.wrap(s.low), b.wrap(s.high), c.Level, s.threshold)
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultStaticExtensions are the file extensions of static assets
+// detected when StaticAssets.Extensions is empty.
+var DefaultStaticExtensions = []string{
+	".css", ".js", ".map", ".ico", ".png", ".jpg", ".jpeg", ".gif", ".svg",
+	".webp", ".avif", ".woff", ".woff2", ".ttf", ".otf", ".eot",
+}
+
+// DefaultStaticContentTypes are the response media types of static assets
+// detected when StaticAssets.ContentTypes is empty.
+var DefaultStaticContentTypes = []string{
+	"text/css",
+	"text/javascript",
+	"application/javascript",
+	"image/*",
+	"font/*",
+}
+
+// StaticAssets configures the detection of static asset requests, such as
+// those served by echo's Static and File handlers, by path extension or
+// response content type. Successful asset requests are logged at Debug,
+// not at all with Drop, or summarized in one entry per Aggregate interval.
+// Failing requests are logged as usual.
+type StaticAssets struct {
+	// Extensions are the path extensions of assets.
+	// Optional. Default value DefaultStaticExtensions.
+	Extensions []string
+
+	// ContentTypes are the response media types of assets, "type/*"
+	// matches any subtype.
+	// Optional. Default value DefaultStaticContentTypes.
+	ContentTypes []string
+
+	// Drop discards successful asset requests.
+	Drop bool
+
+	// Aggregate counts successful asset requests instead of logging them,
+	// and logs the count and bytes served once per interval, and on
+	// Shutdown.
+	// Optional. Default value 0, no aggregation.
+	Aggregate time.Duration
+}
+
+// staticMatcher detects and aggregates static asset requests.
+type staticMatcher struct {
+	extensions   map[string]struct{}
+	contentTypes []string
+	drop         bool
+	interval     time.Duration
+
+	logger *zap.Logger
+	stop   chan struct{}
+	once   sync.Once
+
+	mu    sync.Mutex
+	since time.Time
+	count int64
+	bytes int64
+}
+
+// newStaticMatcher returns the matcher of s, summarizing aggregated
+// requests to l from a background goroutine.
+func newStaticMatcher(s *StaticAssets, l *zap.Logger) *staticMatcher {
+	if s == nil {
+		return nil
+	}
+	exts, types := s.Extensions, s.ContentTypes
+	if len(exts) == 0 {
+		exts = DefaultStaticExtensions
+	}
+	if len(types) == 0 {
+		types = DefaultStaticContentTypes
+	}
+	m := &staticMatcher{
+		extensions:   make(map[string]struct{}, len(exts)),
+		contentTypes: types,
+		drop:         s.Drop,
+		interval:     s.Aggregate,
+		since:        time.Now(),
+		logger:       l,
+		stop:         make(chan struct{}),
+	}
+	for _, ext := range exts {
+		m.extensions[strings.ToLower(ext)] = struct{}{}
+	}
+	if m.interval > 0 {
+		go m.run()
+		closers.add(m)
+	}
+	return m
+}
+
+func (m *staticMatcher) match(c echo.Context) bool {
+	if m == nil {
+		return false
+	}
+	if _, ok := m.extensions[strings.ToLower(path.Ext(c.Request().URL.Path))]; ok {
+		return true
+	}
+	return matchContentType(c.Response().Header().Get(echo.HeaderContentType), m.contentTypes)
+}
+
+// aggregate counts a request.
+func (m *staticMatcher) aggregate(size int64) {
+	m.mu.Lock()
+	m.count++
+	m.bytes += size
+	m.mu.Unlock()
+}
+
+func (m *staticMatcher) run() {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.summarize()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// summarize logs the requests counted since the last summary, if any.
+func (m *staticMatcher) summarize() {
+	m.mu.Lock()
+	count, bytes, since := m.count, m.bytes, m.since
+	now := time.Now()
+	m.count, m.bytes, m.since = 0, 0, now
+	m.mu.Unlock()
+	if count == 0 {
+		return
+	}
+
+	if ce := m.logger.Check(zapcore.InfoLevel, "Static assets served"); ce != nil {
+		ce.Write(
+			zap.Int64("count", count),
+			zap.Int64("bytes", bytes),
+			zap.Duration("interval", now.Sub(since)),
+		)
+	}
+}
+
+// Close stops the background summaries and logs the pending one.
+func (m *staticMatcher) Close() error {
+	if m == nil || m.interval <= 0 {
+		return nil
+	}
+	m.once.Do(func() {
+		close(m.stop)
+		closers.remove(m)
+		m.summarize()
+	})
+	return nil
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedirectStdLog redirects the output of the standard library's global
+// logger to logger at level lv, and returns a function restoring it. The
+// log prefix and flags are stripped, the entry carrying its own time.
+func RedirectStdLog(logger *zap.Logger, lv zapcore.Level) (func(), error) {
+	return zap.RedirectStdLogAt(logger, lv)
+}
+
+// RedirectStderr replaces os.Stderr with a pipe whose lines are logged to
+// logger at level lv, for libraries printing to it directly. It returns a
+// function restoring os.Stderr once the pending lines are logged.
+//
+// Only writes through the os.Stderr variable are captured, not those to
+// file descriptor 2 made by the runtime or cgo. Loggers already writing to
+// "stderr" keep the original file.
+func RedirectStderr(logger *zap.Logger, lv zapcore.Level) (func() error, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	orig := os.Stderr
+	os.Stderr = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if line = strings.TrimRight(line, "\r\n"); line != "" {
+				if ce := logger.Check(lv, line); ce != nil {
+					ce.Write()
+				}
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	return func() error {
+		os.Stderr = orig
+		err := w.Close()
+		<-done
+		if cerr := r.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}, nil
+}
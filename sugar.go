@@ -0,0 +1,18 @@
+package logger
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+// NewSugaredLogger returns the logger of NewLogger wrapped in a
+// zap.SugaredLogger, for key-value style logging.
+func NewSugaredLogger(lv zap.AtomicLevel, opts ...zap.Option) *zap.SugaredLogger {
+	return NewLogger(lv, opts...).Sugar()
+}
+
+// FromContextSugar returns the request-scoped logger of FromContext as a
+// zap.SugaredLogger. The context fields stored by the middleware are kept.
+func FromContextSugar(c echo.Context) *zap.SugaredLogger {
+	return FromContext(c).Sugar()
+}
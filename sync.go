@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// logSyncer syncs a middleware's logger in the background.
+type logSyncer struct {
+	logger *zap.Logger
+	stop   chan struct{}
+	once   sync.Once
+}
+
+func newLogSyncer(logger *zap.Logger, interval time.Duration) *logSyncer {
+	s := &logSyncer{logger: logger, stop: make(chan struct{})}
+	if interval > 0 {
+		go s.run(interval)
+		closers.add(s)
+	}
+	return s
+}
+
+func (s *logSyncer) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.logger.Sync()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Close stops the background sync and syncs the logger.
+func (s *logSyncer) Close() error {
+	s.once.Do(func() {
+		close(s.stop)
+		closers.remove(s)
+	})
+	if err := s.logger.Sync(); err != nil && !isUnsyncable(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package logger
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// SyslogFormat is the message format of a syslog core.
+type SyslogFormat int
+
+// Syslog message formats.
+const (
+	// RFC5424 is the structured syslog protocol.
+	RFC5424 SyslogFormat = iota
+	// RFC3164 is the BSD syslog format understood by every daemon.
+	RFC3164
+)
+
+// Syslog facilities.
+const (
+	FacilityKern   = 0
+	FacilityUser   = 1
+	FacilityDaemon = 3
+	FacilityAuth   = 4
+	FacilityLocal0 = 16
+	FacilityLocal1 = 17
+	FacilityLocal2 = 18
+	FacilityLocal3 = 19
+	FacilityLocal4 = 20
+	FacilityLocal5 = 21
+	FacilityLocal6 = 22
+	FacilityLocal7 = 23
+)
+
+// SyslogFacility returns a pointer to f, for Syslog.Facility.
+func SyslogFacility(f int) *int {
+	return &f
+}
+
+// syslogSockets are the local syslog sockets tried when no address is
+// given.
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// Syslog configures a syslog core.
+type Syslog struct {
+	// Network is "udp", "tcp", "unix" or "unixgram".
+	// Optional. Default value "", the local syslog socket.
+	Network string
+	Address string
+
+	// Format is the message format.
+	// Optional. Default value RFC5424.
+	Format SyslogFormat
+
+	// Facility is the syslog facility, e.g. SyslogFacility(FacilityKern).
+	// Optional. Default value FacilityUser.
+	Facility *int
+
+	// Tag is the application name.
+	// Optional. Default value the program name.
+	Tag string
+
+	// Hostname is the reported host name.
+	// Optional. Default value os.Hostname.
+	Hostname string
+}
+
+// SyslogCore is a zapcore.Core sending entries to a syslog daemon, with
+// zap levels mapped to syslog severities. Entries are encoded by the
+// encoder the core is created with, e.g. a JSON encoder without time and
+// level keys, which syslog records itself.
+type SyslogCore struct {
+	zapcore.LevelEnabler
+	enc  zapcore.Encoder
+	conn *syslogConn
+}
+
+// NewSyslogCore connects to the syslog daemon configured by s.
+func NewSyslogCore(s Syslog, enc zapcore.Encoder, enab zapcore.LevelEnabler) (*SyslogCore, error) {
+	facility := FacilityUser
+	if s.Facility != nil {
+		facility = *s.Facility
+	}
+	if s.Tag == "" {
+		s.Tag = filepath.Base(os.Args[0])
+	}
+	if s.Hostname == "" {
+		s.Hostname, _ = os.Hostname()
+	}
+	conn := &syslogConn{config: s, facility: facility, pid: strconv.Itoa(os.Getpid())}
+	if err := conn.connect(); err != nil {
+		return nil, err
+	}
+	return &SyslogCore{LevelEnabler: enab, enc: enc, conn: conn}, nil
+}
+
+func (c *SyslogCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &SyslogCore{LevelEnabler: c.LevelEnabler, enc: enc, conn: c.conn}
+}
+
+func (c *SyslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *SyslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+	return c.conn.write(ent, strings.TrimRight(buf.String(), "\n"))
+}
+
+func (c *SyslogCore) Sync() error {
+	return nil
+}
+
+// Close closes the connection to the syslog daemon.
+func (c *SyslogCore) Close() error {
+	return c.conn.close()
+}
+
+// syslogSeverity maps a zap level to a syslog severity.
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// syslogConn is a connection to a syslog daemon, shared by the cores
+// derived with With.
+type syslogConn struct {
+	config   Syslog
+	facility int
+	pid      string
+
+	mu     sync.Mutex
+	conn   net.Conn
+	stream bool
+}
+
+func (c *syslogConn) connect() error {
+	if c.config.Network != "" {
+		conn, err := net.Dial(c.config.Network, c.config.Address)
+		if err != nil {
+			return err
+		}
+		c.setConn(conn)
+		return nil
+	}
+	for _, path := range syslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				c.setConn(conn)
+				return nil
+			}
+		}
+	}
+	return errors.New("logger: no local syslog socket found")
+}
+
+// setConn sets the connection, framing messages when it is a stream.
+func (c *syslogConn) setConn(conn net.Conn) {
+	c.conn = conn
+	switch conn.RemoteAddr().Network() {
+	case "tcp", "tcp4", "tcp6", "unix":
+		c.stream = true
+	default:
+		c.stream = false
+	}
+}
+
+func (c *syslogConn) write(ent zapcore.Entry, msg string) error {
+	buf := encoderPool.Get()
+	defer buf.Free()
+	c.format(buf, ent, msg)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+	if _, err := c.conn.Write(c.frame(buf)); err != nil {
+		// Reconnect once, the daemon may have been restarted.
+		c.conn.Close()
+		if err := c.connect(); err != nil {
+			c.conn = nil
+			return err
+		}
+		_, err = c.conn.Write(c.frame(buf))
+		return err
+	}
+	return nil
+}
+
+// format appends the syslog message of an entry to buf.
+func (c *syslogConn) format(buf *buffer.Buffer, ent zapcore.Entry, msg string) {
+	buf.AppendByte('<')
+	buf.AppendInt(int64(c.facility*8 + syslogSeverity(ent.Level)))
+	buf.AppendByte('>')
+	if c.config.Format == RFC3164 {
+		buf.AppendTime(ent.Time, time.Stamp)
+		buf.AppendByte(' ')
+		buf.AppendString(c.config.Hostname)
+		buf.AppendByte(' ')
+		buf.AppendString(c.config.Tag)
+		buf.AppendByte('[')
+		buf.AppendString(c.pid)
+		buf.AppendString("]: ")
+	} else {
+		buf.AppendString("1 ")
+		buf.AppendTime(ent.Time, time.RFC3339Nano)
+		buf.AppendByte(' ')
+		buf.AppendString(syslogField(c.config.Hostname))
+		buf.AppendByte(' ')
+		buf.AppendString(syslogField(c.config.Tag))
+		buf.AppendByte(' ')
+		buf.AppendString(c.pid)
+		buf.AppendString(" - - ")
+	}
+	buf.AppendString(msg)
+}
+
+// frame frames a message for stream transports, with octet counting for
+// RFC 5424 and a trailing newline for RFC 3164.
+func (c *syslogConn) frame(buf *buffer.Buffer) []byte {
+	if !c.stream {
+		return buf.Bytes()
+	}
+	if c.config.Format == RFC3164 {
+		return append(buf.Bytes(), '\n')
+	}
+	return append([]byte(strconv.Itoa(buf.Len())+" "), buf.Bytes()...)
+}
+
+func (c *syslogConn) close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// syslogField returns v as an RFC 5424 header field, "-" when empty.
+func syslogField(v string) string {
+	if v == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(v, " ", "_")
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TeeBuilder composes cores with independent encoders and levels behind a
+// single logger, e.g.
+//
+//	log := logger.NewTeeBuilder().
+//		Console(zapcore.Lock(os.Stderr), zapcore.DebugLevel).
+//		JSON(logger.NewRotatingFile(file), zapcore.InfoLevel).
+//		Core(logger.NewLokiCore(loki, zapcore.WarnLevel)).
+//		Logger(zap.AddCaller())
+type TeeBuilder struct {
+	cores []zapcore.Core
+}
+
+// NewTeeBuilder returns an empty TeeBuilder.
+func NewTeeBuilder() *TeeBuilder {
+	return &TeeBuilder{}
+}
+
+// Core adds core.
+func (b *TeeBuilder) Core(core zapcore.Core) *TeeBuilder {
+	b.cores = append(b.cores, core)
+	return b
+}
+
+// Output adds a core writing the entries enabled by enab to ws with enc.
+func (b *TeeBuilder) Output(enc zapcore.Encoder, ws zapcore.WriteSyncer, enab zapcore.LevelEnabler) *TeeBuilder {
+	return b.Core(zapcore.NewCore(enc, ws, enab))
+}
+
+// Console adds an output in the development console format.
+func (b *TeeBuilder) Console(ws zapcore.WriteSyncer, enab zapcore.LevelEnabler) *TeeBuilder {
+	return b.Output(zapcore.NewConsoleEncoder(NewDevelopmentEncoderConfig()), ws, enab)
+}
+
+// JSON adds an output in the production JSON format of NewLogger.
+func (b *TeeBuilder) JSON(ws zapcore.WriteSyncer, enab zapcore.LevelEnabler) *TeeBuilder {
+	c := zap.NewProductionEncoderConfig()
+	c.EncodeTime = zapcore.ISO8601TimeEncoder
+	return b.Output(zapcore.NewJSONEncoder(c), ws, enab)
+}
+
+// Build returns the tee of the added cores.
+func (b *TeeBuilder) Build() zapcore.Core {
+	return zapcore.NewTee(b.cores...)
+}
+
+// Logger returns a logger writing to the tee of the added cores.
+func (b *TeeBuilder) Logger(opts ...zap.Option) *zap.Logger {
+	return zap.New(b.Build(), opts...)
+}
+
+// WithCore tees a logger's entries to core, next to its output paths.
+func WithCore(core zapcore.Core) Option {
+	return WithZapOptions(zap.WrapCore(func(inner zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(inner, core)
+	}))
+}
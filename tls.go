@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// tlsFields returns the version, cipher suite and SNI server name of a TLS
+// connection.
+func tlsFields(cs *tls.ConnectionState, names FieldNames) []zapcore.Field {
+	if cs == nil {
+		return nil
+	}
+	version := zap.String(names.TLSVersion, tls.VersionName(cs.Version))
+	if names.TLSVersionProtocol != "" {
+		version = zap.String(names.TLSVersion, strings.TrimPrefix(version.String, "TLS "))
+	}
+	fields := []zapcore.Field{
+		version,
+		zap.String(names.TLSCipher, tls.CipherSuiteName(cs.CipherSuite)),
+		zap.String(names.TLSServerName, cs.ServerName),
+	}
+	if names.TLSVersionProtocol != "" {
+		fields = append(fields, zap.String(names.TLSVersionProtocol, "tls"))
+	}
+	return fields
+}
+
+// clientCertFields returns the identity of a verified client certificate.
+func clientCertFields(cs *tls.ConnectionState, names FieldNames) []zapcore.Field {
+	if cs == nil || len(cs.VerifiedChains) == 0 || len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+	cert := cs.PeerCertificates[0]
+	sum := sha256.Sum256(cert.Raw)
+	return []zapcore.Field{
+		zap.String(names.ClientCertSubject, cert.Subject.CommonName),
+		zap.String(names.ClientCertIssuer, cert.Issuer.String()),
+		zap.String(names.ClientCertSerial, cert.SerialNumber.Text(16)),
+		zap.String(names.ClientCertFingerprint, hex.EncodeToString(sum[:])),
+	}
+}
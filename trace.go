@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Trace propagation headers.
+const (
+	HeaderTraceparent = "traceparent"
+	HeaderB3          = "b3"
+	HeaderB3TraceID   = "X-B3-TraceId"
+	HeaderB3SpanID    = "X-B3-SpanId"
+	HeaderB3Sampled   = "X-B3-Sampled"
+	HeaderB3Flags     = "X-B3-Flags"
+)
+
+// TraceContext identifies the trace and span a request belongs to.
+type TraceContext struct {
+	TraceID string
+	SpanID  string
+	// Flags is the hex encoded trace flags, empty when the propagation
+	// format has none.
+	Flags string
+}
+
+// TraceExtractor extracts the trace context of a request. It reports false
+// when the request carries none.
+type TraceExtractor func(c echo.Context) (TraceContext, bool)
+
+// W3CTraceContext extracts the trace context from the traceparent header
+// defined by https://www.w3.org/TR/trace-context/.
+func W3CTraceContext(c echo.Context) (TraceContext, bool) {
+	parts := strings.Split(c.Request().Header.Get(HeaderTraceparent), "-")
+	if len(parts) < 4 {
+		return TraceContext{}, false
+	}
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return TraceContext{}, false
+	}
+	if !isHex(traceID, 32) || !isHex(spanID, 16) || !isHex(flags, 2) {
+		return TraceContext{}, false
+	}
+	if isZero(traceID) || isZero(spanID) {
+		return TraceContext{}, false
+	}
+	return TraceContext{TraceID: traceID, SpanID: spanID, Flags: flags}, true
+}
+
+// B3 extracts the trace context from Zipkin's B3 propagation headers,
+// preferring the single b3 header over the X-B3-* headers. See
+// https://github.com/openzipkin/b3-propagation.
+func B3(c echo.Context) (TraceContext, bool) {
+	h := c.Request().Header
+	if v := h.Get(HeaderB3); v != "" {
+		parts := strings.Split(v, "-")
+		if len(parts) < 2 {
+			// Sampling state only, e.g. "b3: 0".
+			return TraceContext{}, false
+		}
+		var sampled string
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+		return b3TraceContext(parts[0], parts[1], sampled)
+	}
+
+	sampled := h.Get(HeaderB3Sampled)
+	if h.Get(HeaderB3Flags) == "1" {
+		sampled = "d"
+	}
+	return b3TraceContext(h.Get(HeaderB3TraceID), h.Get(HeaderB3SpanID), sampled)
+}
+
+func b3TraceContext(traceID, spanID, sampled string) (TraceContext, bool) {
+	if !isHex(traceID, 32) && !isHex(traceID, 16) || !isHex(spanID, 16) {
+		return TraceContext{}, false
+	}
+	if isZero(traceID) || isZero(spanID) {
+		return TraceContext{}, false
+	}
+	tc := TraceContext{TraceID: traceID, SpanID: spanID}
+	switch sampled {
+	case "1", "d", "true":
+		tc.Flags = "01"
+	case "0", "false":
+		tc.Flags = "00"
+	}
+	return tc, true
+}
+
+// extractTrace returns the fields of the first extractor that finds a trace
+// context.
+func extractTrace(c echo.Context, extractors []TraceExtractor, format func(TraceContext) []zapcore.Field) []zapcore.Field {
+	for _, extract := range extractors {
+		if tc, ok := extract(c); ok {
+			return format(tc)
+		}
+	}
+	return nil
+}
+
+// traceFields logs tc under the trace field names.
+func (n FieldNames) traceFields(tc TraceContext) []zapcore.Field {
+	fields := []zapcore.Field{
+		zap.String(n.TraceID, tc.TraceID),
+		zap.String(n.SpanID, tc.SpanID),
+	}
+	if tc.Flags != "" {
+		fields = append(fields, zap.String(n.TraceFlags, tc.Flags))
+	}
+	return fields
+}
+
+// isHex reports whether s is n lowercase hex digits.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if (ch < '0' || ch > '9') && (ch < 'a' || ch > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
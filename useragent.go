@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultUserAgentCacheSize is the number of parsed user agents cached by
+// UserAgent when no size is given.
+const DefaultUserAgentCacheSize = 1024
+
+// Device classes of UserAgentInfo.
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceOther   = "other"
+)
+
+// UserAgentInfo is a parsed user agent.
+type UserAgentInfo struct {
+	Browser string
+	OS      string
+	Device  string
+}
+
+// UserAgent returns an enricher, for Config.Enrichers, adding the browser
+// family, operating system and device class parsed from the User-Agent
+// header as ua_browser, ua_os and ua_device. The last cacheSize user
+// agents are cached, so each is parsed once.
+func UserAgent(cacheSize int) func(echo.Context) []zapcore.Field {
+	if cacheSize <= 0 {
+		cacheSize = DefaultUserAgentCacheSize
+	}
+	cache := newLRUCache[string, []zapcore.Field](cacheSize)
+
+	return func(c echo.Context) []zapcore.Field {
+		ua := c.Request().UserAgent()
+		if ua == "" {
+			return nil
+		}
+		if fields, ok := cache.get(ua); ok {
+			return fields
+		}
+		info := ParseUserAgent(ua)
+		fields := []zapcore.Field{
+			zap.String("ua_browser", info.Browser),
+			zap.String("ua_os", info.OS),
+			zap.String("ua_device", info.Device),
+		}
+		cache.add(ua, fields)
+		return fields
+	}
+}
+
+// uaBrowsers are browser tokens in match order, since most user agents
+// also carry the tokens of the browsers they derive from.
+var uaBrowsers = []struct{ token, name string }{
+	{"Edg/", "Edge"},
+	{"EdgA/", "Edge"},
+	{"EdgiOS/", "Edge"},
+	{"OPR/", "Opera"},
+	{"Opera", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"YaBrowser/", "Yandex"},
+	{"Vivaldi/", "Vivaldi"},
+	{"Firefox/", "Firefox"},
+	{"FxiOS/", "Firefox"},
+	{"CriOS/", "Chrome"},
+	{"Chrome/", "Chrome"},
+	{"Chromium/", "Chromium"},
+	{"MSIE ", "Internet Explorer"},
+	{"Trident/", "Internet Explorer"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"Go-http-client/", "Go"},
+	{"python-requests/", "Python Requests"},
+	{"okhttp/", "OkHttp"},
+	{"PostmanRuntime/", "Postman"},
+}
+
+// uaOSes are operating system tokens in match order.
+var uaOSes = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"iPod", "iOS"},
+	{"Android", "Android"},
+	{"CrOS", "ChromeOS"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"Linux", "Linux"},
+	{"FreeBSD", "FreeBSD"},
+}
+
+// ParseUserAgent parses the browser family, operating system and device
+// class of a User-Agent header. Unrecognized values are "Other".
+func ParseUserAgent(ua string) UserAgentInfo {
+	info := UserAgentInfo{Browser: "Other", OS: "Other", Device: DeviceOther}
+	for _, b := range uaBrowsers {
+		if strings.Contains(ua, b.token) {
+			info.Browser = b.name
+			break
+		}
+	}
+	for _, o := range uaOSes {
+		if strings.Contains(ua, o.token) {
+			info.OS = o.name
+			break
+		}
+	}
+
+	switch {
+	case defaultBots.match(ua):
+		info.Device = DeviceBot
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") ||
+		info.OS == "Android" && !strings.Contains(ua, "Mobile"):
+		info.Device = DeviceTablet
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod"):
+		info.Device = DeviceMobile
+	case info.OS == "Windows" || info.OS == "macOS" || info.OS == "Linux" ||
+		info.OS == "ChromeOS" || info.OS == "FreeBSD":
+		info.Device = DeviceDesktop
+	}
+	return info
+}
@@ -0,0 +1,177 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultW3CFields are the fields of the #Fields directive used when none
+// are given to NewW3CEncoder.
+var DefaultW3CFields = []string{
+	"date", "time", "c-ip", "cs-method", "cs-uri-stem", "cs-uri-query",
+	"sc-status", "sc-bytes", "time-taken", "cs(User-Agent)",
+}
+
+// w3cEncoder renders access log entries in the W3C Extended Log File
+// Format. Entries that are not access log entries are dropped.
+type w3cEncoder struct {
+	*zapcore.MapObjectEncoder
+	names  FieldNames
+	fields []string
+	header *sync.Once
+}
+
+// NewW3CEncoder returns an encoder rendering access log entries, whose
+// fields are named by names, in the W3C Extended Log File Format with the
+// given #Fields directive. The directives are written before the first
+// entry. Supported fields are date, time, c-ip, cs-method, cs-uri,
+// cs-uri-stem, cs-uri-query, cs-username, cs-version, cs-host, sc-status,
+// sc-bytes, time-taken, cs(User-Agent) and cs(Referer); others are logged
+// as "-".
+// See https://www.w3.org/TR/WD-logfile.html.
+func NewW3CEncoder(names FieldNames, fields ...string) zapcore.Encoder {
+	if len(fields) == 0 {
+		fields = DefaultW3CFields
+	}
+	return &w3cEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		names:            names.withDefaults(),
+		fields:           fields,
+		header:           new(sync.Once),
+	}
+}
+
+// NewW3CCore returns a core writing access log entries to ws in the W3C
+// Extended Log File Format.
+func NewW3CCore(ws zapcore.WriteSyncer, enab zapcore.LevelEnabler, names FieldNames, fields ...string) zapcore.Core {
+	return zapcore.NewCore(NewW3CEncoder(names, fields...), ws, enab)
+}
+
+// WithW3COutput tees a logger's entries to a W3C Extended Log File Format
+// core writing to ws, next to the logger's own core.
+func WithW3COutput(ws zapcore.WriteSyncer, names FieldNames, fields ...string) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(core, NewW3CCore(ws, core, names, fields...))
+	})
+}
+
+func (e *w3cEncoder) Clone() zapcore.Encoder {
+	m := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		m.Fields[k] = v
+	}
+	return &w3cEncoder{
+		MapObjectEncoder: m,
+		names:            e.names,
+		fields:           e.fields,
+		header:           e.header,
+	}
+}
+
+func (e *w3cEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	m := e.Clone().(*w3cEncoder).MapObjectEncoder
+	for _, f := range fields {
+		f.AddTo(m)
+	}
+
+	buf := encoderPool.Get()
+	method, uri := w3cRequest(m.Fields, e.names)
+	if uri == "" {
+		return buf, nil
+	}
+
+	e.header.Do(func() {
+		buf.AppendString("#Version: 1.0")
+		buf.AppendString(zapcore.DefaultLineEnding)
+		buf.AppendString("#Date: ")
+		buf.AppendTime(ent.Time.UTC(), "2006-01-02 15:04:05")
+		buf.AppendString(zapcore.DefaultLineEnding)
+		buf.AppendString("#Fields: ")
+		buf.AppendString(strings.Join(e.fields, " "))
+		buf.AppendString(zapcore.DefaultLineEnding)
+	})
+
+	stem, query, _ := strings.Cut(uri, "?")
+	for i, f := range e.fields {
+		if i > 0 {
+			buf.AppendByte(' ')
+		}
+		var v string
+		switch f {
+		case "date":
+			v = ent.Time.UTC().Format("2006-01-02")
+		case "time":
+			v = ent.Time.UTC().Format("15:04:05")
+		case "c-ip":
+			v = w3cValue(m.Fields[e.names.RemoteIP])
+		case "cs-method":
+			v = method
+		case "cs-uri":
+			v = uri
+		case "cs-uri-stem":
+			v = stem
+		case "cs-uri-query":
+			v = query
+		case "cs-username":
+			v = w3cValue(m.Fields[e.names.AuthUser])
+		case "cs-version":
+			v = w3cValue(m.Fields[e.names.Protocol])
+		case "cs-host":
+			v = w3cValue(m.Fields[e.names.Host])
+		case "sc-status":
+			v = w3cValue(m.Fields[e.names.Status])
+		case "sc-bytes":
+			v = w3cValue(m.Fields[e.names.Size])
+		case "time-taken":
+			v = w3cValue(m.Fields[e.names.Latency])
+		case "cs(User-Agent)":
+			v = w3cValue(m.Fields[e.names.UserAgent])
+		case "cs(Referer)":
+			v = w3cValue(m.Fields[e.names.Referer])
+		}
+		if v == "" {
+			v = "-"
+		}
+		buf.AppendString(strings.ReplaceAll(v, " ", "+"))
+	}
+	buf.AppendString(zapcore.DefaultLineEnding)
+
+	return buf, nil
+}
+
+// w3cRequest returns the method and URI of an access log entry. The URI is
+// "" when the entry has none.
+func w3cRequest(fields map[string]interface{}, names FieldNames) (string, string) {
+	method, _ := fields[names.Method].(string)
+	uri, _ := fields[names.URI].(string)
+	if uri != "" {
+		return method, uri
+	}
+	if r, ok := fields[names.Request].(string); ok {
+		if m, u, ok := strings.Cut(r, " "); ok {
+			return m, u
+		}
+	}
+	return method, ""
+}
+
+// w3cValue formats a field value, durations in seconds.
+func w3cValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Duration:
+		return strconv.FormatFloat(v.Seconds(), 'f', 3, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}